@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "github.com/chamanbravo/upstat/docs"
 	appLayer "github.com/chamanbravo/upstat/internal/app"
@@ -37,13 +42,15 @@ func main() {
 
 	repo := repository.New(db)
 
-	monitor := pkg.New(repo)
+	dispatcher := pkg.NewDispatcher(repo)
+	monitor := pkg.New(repo, dispatcher)
 
-	aLayer := appLayer.New(repo, monitor)
+	aLayer := appLayer.New(repo, monitor, dispatcher)
 
 	h := controllers.New(aLayer)
 
-	monitor.StartGoroutineSetup()
+	dispatcher.Start()
+	monitor.Start()
 
 	routes.AuthRoutes(app, h)
 	routes.SwaggerRoute(app)
@@ -51,6 +58,57 @@ func main() {
 	routes.UserRoutes(app, h)
 	routes.NotificationRoutes(app, h)
 	routes.StatusPagesRoutes(app, h)
+	routes.SchedulerRoutes(app, h)
+	routes.IncidentRoutes(app, h)
+	routes.StatusPageIncidentRoutes(app, h)
+	routes.MaintenanceRoutes(app, h)
 
-	log.Fatal(app.Listen(":8000"))
+	go func() {
+		if err := app.Listen(":8000"); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+
+	if err := app.ShutdownWithContext(shutdownCtx); err != nil {
+		log.Println("Error when shutting down the server:", err)
+	}
+
+	if err := monitor.Stop(shutdownCtx); err != nil {
+		log.Println("Error when stopping the monitors:", err)
+	}
+
+	if err := dispatcher.Stop(shutdownCtx); err != nil {
+		log.Println("Error when stopping the notification dispatcher:", err)
+	}
+
+	if err := db.Close(); err != nil {
+		log.Println("Error when closing the database:", err)
+	}
+}
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// shutdownTimeout reads SHUTDOWN_TIMEOUT, a duration such as "30s", which
+// bounds how long the server waits for requests and checks to finish.
+func shutdownTimeout() time.Duration {
+	value := os.Getenv("SHUTDOWN_TIMEOUT")
+	if value == "" {
+		return defaultShutdownTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Printf("Invalid SHUTDOWN_TIMEOUT value %q, using %v", value, defaultShutdownTimeout)
+		return defaultShutdownTimeout
+	}
+
+	return timeout
 }
@@ -1,10 +1,13 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/chamanbravo/upstat/internal/dto"
 	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/alerts"
 )
 
 type DB interface {
@@ -20,7 +23,8 @@ type DB interface {
 	FindNotificationById(id int) (*models.Notification, error)
 	UpdateNotificationById(id int, nc *dto.NotificationCreateIn) error
 	DeleteNotificationChannel(id int) error
-	FindNotificationChannelsByMonitorId(id int) ([]models.Notification, error)
+	FindNotificationChannelsByMonitorId(ctx context.Context, id int) ([]models.Notification, error)
+	RetrieveDeliveries(notificationId, limit int) ([]*models.NotificationDelivery, error)
 
 	CreateStatusPage(u *dto.CreateStatusPageIn) error
 	ListStatusPages() ([]*models.StatusPage, error)
@@ -34,34 +38,70 @@ type DB interface {
 	UpdateStatusPageMonitorById(monitorId int, statusPages []string) error
 	FindStatusPageByMonitorId(id int) ([]models.StatusPage, error)
 
+	CreateStatusPageIncident(in *dto.CreateStatusPageIncidentIn, username string) (int, error)
+	UpdateStatusPageIncident(id int, in *dto.StatusPageIncidentIn) error
+	DeleteStatusPageIncident(id int) error
+	AddStatusPageIncidentUpdate(update *models.StatusPageIncidentUpdate) error
+	FindStatusPageIncidentById(id int) (*models.StatusPageIncident, error)
+	ListStatusPageIncidents() ([]*models.StatusPageIncident, error)
+	StatusPageIncidentsBySlug(slug string, since time.Time) ([]*models.StatusPageIncident, error)
+
+	CreateMaintenanceWindow(in *dto.MaintenanceWindowIn) (int, error)
+	UpdateMaintenanceWindow(id int, in *dto.MaintenanceWindowIn) error
+	DeleteMaintenanceWindow(id int) error
+	FindMaintenanceWindowById(id int) (*models.MaintenanceWindow, error)
+	ListMaintenanceWindows() ([]*models.MaintenanceWindow, error)
+	MaintenanceWindowsBySlug(slug string) ([]*models.MaintenanceWindow, error)
+
 	CreateMonitor(u *dto.AddMonitorIn) (*models.Monitor, error)
 	DeleteMonitorById(id int) error
-	RetrieveUptime(id int, timestamp time.Time) (float64, error)
-	RetrieveMonitors() ([]*models.Monitor, error)
+	RetrieveUptime(id int, timestamp time.Time, excludeMaintenance bool) (float64, error)
+	RetrieveDegradedTime(id int, timestamp time.Time, excludeMaintenance bool) (float64, error)
+	RetrieveMonitors(ctx context.Context) ([]*models.Monitor, error)
 	UpdateMonitorById(id int, monitor *dto.AddMonitorIn) error
 	RetrieveAverageLatency(id int, timestamp time.Time) (float64, error)
-	UpdateMonitorStatus(id int, status string) error
-	FindMonitorById(id int) (*models.Monitor, error)
+	UpdateMonitorStatus(ctx context.Context, id int, status string) error
+	PauseMonitor(id int, pausedBy string, resumeAt *time.Time) error
+	ResumeMonitor(ctx context.Context, id int) error
+	FindMonitorById(ctx context.Context, id int) (*models.Monitor, error)
 	RetrieveHeartbeats(id, limit int) ([]*models.Heartbeat, error)
+	RetrieveHeartbeatsBetween(id int, from, to time.Time, limit int) ([]*models.Heartbeat, error)
+
+	FindIncidentById(id int) (*models.Incident, error)
+	RetrieveIncidents(filter *dto.IncidentsIn, limit int) ([]*models.Incident, error)
+	AcknowledgeIncident(incident *models.Incident, username string, acknowledgedAt time.Time) error
+	SaveIncidentNote(note *models.IncidentNote) error
+	RetrieveIncidentNotes(incidentId int) ([]*models.IncidentNote, error)
 
 	UpdateNotificationMonitorById(monitorId int, notificationChannels []string) error
 	NotificationMonitor(monitorId int, notificationChannels []string) error
 }
 
 type Monitor interface {
-	StartGoroutine(monitor *models.Monitor)
-	StopGoroutine(id int)
-	StartGoroutineSetup()
+	Schedule(monitor *models.Monitor)
+	Unschedule(id int)
+	Start()
+	ScheduleResume(monitor *models.Monitor)
+	CheckNow(ctx context.Context, monitor *models.Monitor) *models.Heartbeat
+	Stats() dto.SchedulerStats
+	Notify(ctx context.Context, alert *alerts.Alert)
+	InvalidateMaintenance()
+}
+
+type Dispatcher interface {
+	Test(ctx context.Context, provider string, data json.RawMessage) (*alerts.Response, error)
 }
 
 type App struct {
-	db      DB
-	monitor Monitor
+	db         DB
+	monitor    Monitor
+	dispatcher Dispatcher
 }
 
-func New(db DB, m Monitor) *App {
+func New(db DB, m Monitor, d Dispatcher) *App {
 	return &App{
-		db:      db,
-		monitor: m,
+		db:         db,
+		monitor:    m,
+		dispatcher: d,
 	}
 }
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/alerts"
+)
+
+const (
+	// incidentMargin is how long before and after an incident its
+	// heartbeats are included in the incident details.
+	incidentMargin        = 15 * time.Minute
+	maxIncidentHeartbeats = 1000
+)
+
+// RetrieveIncidents returns a page of incidents and the cursor of the
+// next page, which is 0 when this is the last one.
+func (a *App) RetrieveIncidents(filter *dto.IncidentsIn) ([]*models.Incident, int, error) {
+	incidents, err := a.db.RetrieveIncidents(filter, filter.Limit+1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(incidents) <= filter.Limit {
+		return incidents, 0, nil
+	}
+
+	incidents = incidents[:filter.Limit]
+	return incidents, incidents[len(incidents)-1].ID, nil
+}
+
+// FindIncidentById returns the incident along with the heartbeats of its
+// monitor from shortly before it started until shortly after it ended.
+func (a *App) FindIncidentById(id int) (*models.Incident, []*models.Heartbeat, error) {
+	incident, err := a.db.FindIncidentById(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	end := time.Now()
+	if incident.ResolvedAt != nil {
+		end = incident.ResolvedAt.Add(incidentMargin)
+	}
+	start := end.Add(-incidentMargin)
+	if incident.StartedAt != nil {
+		start = incident.StartedAt.Add(-incidentMargin)
+	}
+
+	heartbeats, err := a.db.RetrieveHeartbeatsBetween(incident.MonitorId, start, end, maxIncidentHeartbeats)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return incident, heartbeats, nil
+}
+
+func (a *App) RetrieveIncidentNotes(id int) ([]*models.IncidentNote, error) {
+	return a.db.RetrieveIncidentNotes(id)
+}
+
+// AcknowledgeIncident marks the open incident as handled by the user and
+// lets the notification channels of its monitor know.
+func (a *App) AcknowledgeIncident(ctx context.Context, id int, username string) (*models.Incident, error) {
+	incident, err := a.db.FindIncidentById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.db.AcknowledgeIncident(incident, username, time.Now()); err != nil {
+		return nil, err
+	}
+
+	a.broadcast(ctx, incident, &alerts.Alert{Event: alerts.EventAcknowledged, User: username})
+
+	return incident, nil
+}
+
+// AddIncidentNote attaches the note of the user to the incident and sends
+// it to the notification channels of its monitor.
+func (a *App) AddIncidentNote(ctx context.Context, id int, username, text string) (*models.IncidentNote, error) {
+	incident, err := a.db.FindIncidentById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &models.IncidentNote{IncidentId: incident.ID, Username: username, Text: text, CreatedAt: time.Now()}
+	if err := a.db.SaveIncidentNote(note); err != nil {
+		return nil, err
+	}
+
+	a.broadcast(ctx, incident, &alerts.Alert{Event: alerts.EventNote, User: username, Note: note})
+
+	return note, nil
+}
+
+// broadcast fills in the incident, its monitor and the latest heartbeat
+// of the monitor and queues the alert for its notification channels.
+// Channels never heard of incidents opened during maintenance, so nothing
+// is sent about them.
+func (a *App) broadcast(ctx context.Context, incident *models.Incident, alert *alerts.Alert) {
+	if incident.MaintenanceId != nil {
+		return
+	}
+
+	monitor, err := a.db.FindMonitorById(ctx, incident.MonitorId)
+	if err != nil {
+		log.Printf("Error when trying to retrieve monitor of incident %d: %v", incident.ID, err.Error())
+		return
+	}
+
+	heartbeat := &models.Heartbeat{MonitorId: monitor.ID}
+	heartbeats, err := a.db.RetrieveHeartbeats(monitor.ID, 1)
+	if err == nil && len(heartbeats) > 0 {
+		heartbeat = heartbeats[0]
+	}
+
+	alert.Type = incident.Type
+	alert.Monitor = monitor
+	alert.Heartbeat = heartbeat
+	alert.Incident = incident
+	alert.Time = time.Now()
+
+	a.monitor.Notify(ctx, alert)
+}
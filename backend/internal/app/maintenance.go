@@ -0,0 +1,83 @@
+package app
+
+import (
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/maintenance"
+)
+
+// upcomingMaintenanceWindow is how far ahead status pages announce
+// maintenance.
+const upcomingMaintenanceWindow = 7 * 24 * time.Hour
+
+func (a *App) CreateMaintenanceWindow(in *dto.MaintenanceWindowIn) (*models.MaintenanceWindow, error) {
+	id, err := a.db.CreateMaintenanceWindow(in)
+	if err != nil {
+		return nil, err
+	}
+	a.monitor.InvalidateMaintenance()
+
+	return a.db.FindMaintenanceWindowById(id)
+}
+
+func (a *App) UpdateMaintenanceWindow(id int, in *dto.MaintenanceWindowIn) (*models.MaintenanceWindow, error) {
+	if err := a.db.UpdateMaintenanceWindow(id, in); err != nil {
+		return nil, err
+	}
+	a.monitor.InvalidateMaintenance()
+
+	return a.db.FindMaintenanceWindowById(id)
+}
+
+func (a *App) DeleteMaintenanceWindow(id int) error {
+	if err := a.db.DeleteMaintenanceWindow(id); err != nil {
+		return err
+	}
+	a.monitor.InvalidateMaintenance()
+
+	return nil
+}
+
+func (a *App) FindMaintenanceWindowById(id int) (*models.MaintenanceWindow, error) {
+	return a.db.FindMaintenanceWindowById(id)
+}
+
+func (a *App) ListMaintenanceWindows() ([]*models.MaintenanceWindow, error) {
+	return a.db.ListMaintenanceWindows()
+}
+
+// StatusPageMaintenance returns the maintenance of the status page that
+// is in progress and the one starting within the next week.
+func (a *App) StatusPageMaintenance(slug string) ([]*dto.MaintenanceOccurrence, []*dto.MaintenanceOccurrence, error) {
+	windows, err := a.db.MaintenanceWindowsBySlug(slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	active := make([]*dto.MaintenanceOccurrence, 0)
+	upcoming := make([]*dto.MaintenanceOccurrence, 0)
+	for _, window := range windows {
+		start, end, ok := maintenance.Occurrence(window, now)
+		if !ok || start.After(now.Add(upcomingMaintenanceWindow)) {
+			continue
+		}
+
+		occurrence := &dto.MaintenanceOccurrence{
+			ID:          window.ID,
+			Title:       window.Title,
+			Description: window.Description,
+			StartsAt:    start,
+			EndsAt:      end,
+		}
+		if start.After(now) {
+			upcoming = append(upcoming, occurrence)
+		} else {
+			active = append(active, occurrence)
+		}
+	}
+
+	return active, upcoming, nil
+}
@@ -1,13 +1,19 @@
 package app
 
 import (
+	"context"
 	"time"
 
 	"github.com/chamanbravo/upstat/internal/dto"
 	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/svcerr"
 )
 
 func (a *App) CreateMonitor(u *dto.AddMonitorIn) (*models.Monitor, error) {
+	if u.AuthMethod == "bearer" && u.AuthToken == "" {
+		return nil, svcerr.ErrMonitorAuthTokenMissing
+	}
+
 	return a.db.CreateMonitor(u)
 }
 
@@ -15,28 +21,98 @@ func (a *App) DeleteMonitorById(id int) error {
 	return a.db.DeleteMonitorById(id)
 }
 
-func (a *App) RetrieveUptime(id int, timestamp time.Time) (float64, error) {
-	return a.db.RetrieveUptime(id, timestamp)
+func (a *App) RetrieveUptime(id int, timestamp time.Time, excludeMaintenance bool) (float64, error) {
+	return a.db.RetrieveUptime(id, timestamp, excludeMaintenance)
+}
+
+func (a *App) RetrieveDegradedTime(id int, timestamp time.Time, excludeMaintenance bool) (float64, error) {
+	return a.db.RetrieveDegradedTime(id, timestamp, excludeMaintenance)
 }
 
 func (a *App) RetrieveAverageLatency(id int, timestamp time.Time) (float64, error) {
 	return a.db.RetrieveAverageLatency(id, timestamp)
 }
 
-func (a *App) UpdateMonitorStatus(id int, status string) error {
-	return a.db.UpdateMonitorStatus(id, status)
+func (a *App) UpdateMonitorStatus(ctx context.Context, id int, status string) error {
+	return a.db.UpdateMonitorStatus(ctx, id, status)
+}
+
+func (a *App) PauseMonitor(ctx context.Context, id int, pausedBy string, resumeAt *time.Time) error {
+	if err := a.db.PauseMonitor(id, pausedBy, resumeAt); err != nil {
+		return err
+	}
+	a.monitor.Unschedule(id)
+
+	if resumeAt != nil {
+		monitor, err := a.db.FindMonitorById(ctx, id)
+		if err != nil {
+			return err
+		}
+		a.monitor.ScheduleResume(monitor)
+	}
+
+	return nil
 }
 
-func (a *App) RetrieveMonitors() ([]*models.Monitor, error) {
-	return a.db.RetrieveMonitors()
+func (a *App) ResumeMonitor(ctx context.Context, id int) error {
+	if err := a.db.ResumeMonitor(ctx, id); err != nil {
+		return err
+	}
+
+	monitor, err := a.db.FindMonitorById(ctx, id)
+	if err != nil {
+		return err
+	}
+	a.monitor.Schedule(monitor)
+
+	return nil
 }
 
-func (a *App) UpdateMonitorById(id int, monitor *dto.AddMonitorIn) error {
-	return a.db.UpdateMonitorById(id, monitor)
+func (a *App) RetrieveMonitors(ctx context.Context) ([]*models.Monitor, error) {
+	return a.db.RetrieveMonitors(ctx)
 }
 
-func (a *App) FindMonitorById(id int) (*models.Monitor, error) {
-	return a.db.FindMonitorById(id)
+// UpdateMonitorById saves the monitor. Credentials are never sent to
+// clients, so the saved ones are kept unless new ones are given or they
+// are cleared.
+func (a *App) UpdateMonitorById(ctx context.Context, id int, u *dto.AddMonitorIn) error {
+	saved, err := a.db.FindMonitorById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if u.AuthPassword == "" && !u.ClearAuthPassword {
+		u.AuthPassword = saved.AuthPassword
+	}
+	if u.AuthToken == "" && !u.ClearAuthToken {
+		u.AuthToken = saved.AuthToken
+	}
+
+	if u.AuthMethod == "bearer" && u.AuthToken == "" {
+		return svcerr.ErrMonitorAuthTokenMissing
+	}
+
+	return a.db.UpdateMonitorById(id, u)
+}
+
+// RescheduleMonitor applies the saved configuration of the monitor to the
+// scheduler, it is called once all of an update has been saved.
+func (a *App) RescheduleMonitor(ctx context.Context, id int) error {
+	monitor, err := a.db.FindMonitorById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if monitor.Active {
+		a.monitor.Schedule(monitor)
+	} else {
+		a.monitor.Unschedule(id)
+	}
+
+	return nil
+}
+
+func (a *App) FindMonitorById(ctx context.Context, id int) (*models.Monitor, error) {
+	return a.db.FindMonitorById(ctx, id)
 }
 
 func (a *App) RetrieveHeartbeats(id, limit int) ([]*models.Heartbeat, error) {
@@ -44,9 +120,31 @@ func (a *App) RetrieveHeartbeats(id, limit int) ([]*models.Heartbeat, error) {
 }
 
 func (a *App) StartMonitoringProcess(m *models.Monitor) {
-	a.monitor.StartGoroutine(m)
+	if m.Active {
+		a.monitor.Schedule(m)
+	} else if m.ResumeAt != nil {
+		a.monitor.ScheduleResume(m)
+	}
 }
 
 func (a *App) StopMonitoringProcess(id int) {
-	a.monitor.StopGoroutine(id)
+	a.monitor.Unschedule(id)
+}
+
+func (a *App) CheckMonitor(ctx context.Context, id int) (*models.Heartbeat, error) {
+	monitor, err := a.db.FindMonitorById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	heartbeat := a.monitor.CheckNow(ctx, monitor)
+	if heartbeat == nil {
+		return nil, ctx.Err()
+	}
+
+	return heartbeat, nil
+}
+
+func (a *App) SchedulerStats() dto.SchedulerStats {
+	return a.monitor.Stats()
 }
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"context"
 	"github.com/chamanbravo/upstat/internal/dto"
 	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/alerts"
 )
 
 func (a *App) CreateNotificationChannel(nc *dto.NotificationCreateIn) error {
@@ -17,6 +19,10 @@ func (a *App) FindNotificationById(id int) (*models.Notification, error) {
 	return a.db.FindNotificationById(id)
 }
 
+func (a *App) TestNotification(ctx context.Context, nc *dto.NotificationCreateIn) (*alerts.Response, error) {
+	return a.dispatcher.Test(ctx, nc.Provider, nc.Data)
+}
+
 func (a *App) UpdateNotificationById(id int, nc *dto.NotificationCreateIn) error {
 	return a.db.UpdateNotificationById(id, nc)
 }
@@ -33,6 +39,10 @@ func (a *App) NotificationMonitor(monitorId int, notificationChannels []string)
 	return a.db.NotificationMonitor(monitorId, notificationChannels)
 }
 
-func (a *App) FindNotificationChannelsByMonitorId(id int) ([]models.Notification, error) {
-	return a.db.FindNotificationChannelsByMonitorId(id)
+func (a *App) FindNotificationChannelsByMonitorId(ctx context.Context, id int) ([]models.Notification, error) {
+	return a.db.FindNotificationChannelsByMonitorId(ctx, id)
+}
+
+func (a *App) RetrieveDeliveries(notificationId, limit int) ([]*models.NotificationDelivery, error) {
+	return a.db.RetrieveDeliveries(notificationId, limit)
 }
@@ -16,7 +16,13 @@ func (a *App) ListStatusPages() ([]*models.StatusPage, error) {
 }
 
 func (a *App) DeleteStatusPageById(id int) error {
-	return a.db.DeleteStatusPageById(id)
+	if err := a.db.DeleteStatusPageById(id); err != nil {
+		return err
+	}
+	// Monitors are under the maintenance of their status pages.
+	a.monitor.InvalidateMaintenance()
+
+	return nil
 }
 
 func (a *App) UpdateStatusPage(id int, statusPage *dto.CreateStatusPageIn) error {
@@ -40,11 +46,21 @@ func (a *App) RetrieveHeartbeatsByTime(id int, startTime time.Time) ([]*models.H
 }
 
 func (a *App) StatusPageMonitor(monitorId int, statusPages []string) error {
-	return a.db.StatusPageMonitor(monitorId, statusPages)
+	if err := a.db.StatusPageMonitor(monitorId, statusPages); err != nil {
+		return err
+	}
+	a.monitor.InvalidateMaintenance()
+
+	return nil
 }
 
 func (a *App) UpdateStatusPageMonitorById(monitorId int, statusPages []string) error {
-	return a.db.UpdateStatusPageMonitorById(monitorId, statusPages)
+	if err := a.db.UpdateStatusPageMonitorById(monitorId, statusPages); err != nil {
+		return err
+	}
+	a.monitor.InvalidateMaintenance()
+
+	return nil
 }
 
 func (a *App) FindStatusPageByMonitorId(id int) ([]models.StatusPage, error) {
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+// recentIncidentsWindow is how long resolved incidents stay on status pages.
+const recentIncidentsWindow = 7 * 24 * time.Hour
+
+func (a *App) CreateStatusPageIncident(in *dto.CreateStatusPageIncidentIn, username string) (*models.StatusPageIncident, error) {
+	id, err := a.db.CreateStatusPageIncident(in, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.db.FindStatusPageIncidentById(id)
+}
+
+func (a *App) UpdateStatusPageIncident(id int, in *dto.StatusPageIncidentIn) (*models.StatusPageIncident, error) {
+	if err := a.db.UpdateStatusPageIncident(id, in); err != nil {
+		return nil, err
+	}
+
+	return a.db.FindStatusPageIncidentById(id)
+}
+
+func (a *App) DeleteStatusPageIncident(id int) error {
+	return a.db.DeleteStatusPageIncident(id)
+}
+
+func (a *App) AddStatusPageIncidentUpdate(id int, in *dto.StatusPageIncidentUpdateIn, username string) (*models.StatusPageIncidentUpdate, error) {
+	update := &models.StatusPageIncidentUpdate{
+		IncidentId: id,
+		Status:     in.Status,
+		Message:    in.Message,
+		Username:   username,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := a.db.AddStatusPageIncidentUpdate(update); err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+func (a *App) FindStatusPageIncidentById(id int) (*models.StatusPageIncident, error) {
+	return a.db.FindStatusPageIncidentById(id)
+}
+
+func (a *App) ListStatusPageIncidents() ([]*models.StatusPageIncident, error) {
+	return a.db.ListStatusPageIncidents()
+}
+
+// StatusPageIncidents returns the open incidents of the status page and
+// the ones resolved within the last week.
+func (a *App) StatusPageIncidents(slug string) ([]*models.StatusPageIncident, []*models.StatusPageIncident, error) {
+	incidents, err := a.db.StatusPageIncidentsBySlug(slug, time.Now().Add(-recentIncidentsWindow))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	active := make([]*models.StatusPageIncident, 0)
+	recent := make([]*models.StatusPageIncident, 0)
+	for _, incident := range incidents {
+		if incident.ResolvedAt == nil {
+			active = append(active, incident)
+		} else {
+			recent = append(recent, incident)
+		}
+	}
+
+	return active, recent, nil
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/pkg"
+	"github.com/chamanbravo/upstat/svcerr"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Param monitorId query int false "Monitor ID"
+// @Param status query string false "open or resolved"
+// @Param type query string false "DOWN or DEGRADED"
+// @Param from query string false "Incidents open at or after this time (RFC 3339)"
+// @Param to query string false "Incidents open at or before this time (RFC 3339)"
+// @Param cursor query int false "nextCursor of the previous page"
+// @Param limit query int false "Page size (default 50)"
+// @Param sort query string false "asc or desc (default desc)"
+// @Success 200 {object} dto.IncidentsOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/incidents [get]
+func (h *Handler) IncidentsList(c *fiber.Ctx) error {
+	return h.listIncidents(c, 0)
+}
+
+// @Tags Monitors
+// @Accept json
+// @Produce json
+// @Param id path string true "Monitor ID"
+// @Param status query string false "open or resolved"
+// @Param type query string false "DOWN or DEGRADED"
+// @Param from query string false "Incidents open at or after this time (RFC 3339)"
+// @Param to query string false "Incidents open at or before this time (RFC 3339)"
+// @Param cursor query int false "nextCursor of the previous page"
+// @Param limit query int false "Page size (default 50)"
+// @Param sort query string false "asc or desc (default desc)"
+// @Success 200 {object} dto.IncidentsOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/monitors/{id}/incidents [get]
+func (h *Handler) MonitorIncidents(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	return h.listIncidents(c, id)
+}
+
+func (h *Handler) listIncidents(c *fiber.Ctx, monitorId int) error {
+	query := new(dto.IncidentsIn)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(query)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	if monitorId != 0 {
+		query.MonitorId = monitorId
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	incidents, nextCursor, err := h.app.RetrieveIncidents(query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":    "success",
+		"incidents":  incidents,
+		"nextCursor": nextCursor,
+	})
+}
+
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Success 200 {object} dto.IncidentInfo
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/incidents/{id} [get]
+func (h *Handler) IncidentInfo(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	incident, heartbeats, err := h.app.FindIncidentById(id)
+	if err != nil {
+		return incidentError(c, err)
+	}
+
+	notes, err := h.app.RetrieveIncidentNotes(id)
+	if err != nil {
+		return incidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":    "success",
+		"incident":   incident,
+		"notes":      notes,
+		"heartbeats": heartbeats,
+	})
+}
+
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Success 200 {object} dto.IncidentOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/incidents/{id}/acknowledge [post]
+func (h *Handler) AcknowledgeIncident(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	username, _ := c.Locals("username").(string)
+	incident, err := h.app.AcknowledgeIncident(c.UserContext(), id, username)
+	if err != nil {
+		return incidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":  "Incident acknowledged.",
+		"incident": incident,
+	})
+}
+
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Param body body dto.IncidentNoteIn true "Body"
+// @Success 200 {object} dto.IncidentNoteOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/incidents/{id}/notes [post]
+func (h *Handler) AddIncidentNote(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	body := new(dto.IncidentNoteIn)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(body)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	username, _ := c.Locals("username").(string)
+	note, err := h.app.AddIncidentNote(c.UserContext(), id, username, body.Text)
+	if err != nil {
+		return incidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message": "Note added.",
+		"note":    note,
+	})
+}
+
+func incidentError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case svcerr.IsNotFound(err):
+		status = fiber.StatusNotFound
+	case errors.Is(err, svcerr.ErrIncidentClosed):
+		status = fiber.StatusConflict
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/pkg"
+	"github.com/chamanbravo/upstat/svcerr"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Tags Maintenance
+// @Accept json
+// @Produce json
+// @Param body body dto.MaintenanceWindowIn true "Body"
+// @Success 200 {object} dto.MaintenanceWindowOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/maintenance [post]
+func (h *Handler) CreateMaintenanceWindow(c *fiber.Ctx) error {
+	body := new(dto.MaintenanceWindowIn)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(body)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	window, err := h.app.CreateMaintenanceWindow(body)
+	if err != nil {
+		return maintenanceWindowError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":           "Maintenance window created.",
+		"maintenanceWindow": window,
+	})
+}
+
+// @Tags Maintenance
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.MaintenanceWindowsOut
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/maintenance [get]
+func (h *Handler) ListMaintenanceWindows(c *fiber.Ctx) error {
+	windows, err := h.app.ListMaintenanceWindows()
+	if err != nil {
+		return maintenanceWindowError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":            "success",
+		"maintenanceWindows": windows,
+	})
+}
+
+// @Tags Maintenance
+// @Accept json
+// @Produce json
+// @Param id path string true "Maintenance Window ID"
+// @Success 200 {object} dto.MaintenanceWindowOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/maintenance/{id} [get]
+func (h *Handler) MaintenanceWindowInfo(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+	window, err := h.app.FindMaintenanceWindowById(id)
+	if err != nil {
+		return maintenanceWindowError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":           "success",
+		"maintenanceWindow": window,
+	})
+}
+
+// @Tags Maintenance
+// @Accept json
+// @Produce json
+// @Param id path string true "Maintenance Window ID"
+// @Param body body dto.MaintenanceWindowIn true "Body"
+// @Success 200 {object} dto.MaintenanceWindowOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/maintenance/{id} [patch]
+func (h *Handler) UpdateMaintenanceWindow(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+	body := new(dto.MaintenanceWindowIn)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(body)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	window, err := h.app.UpdateMaintenanceWindow(id, body)
+	if err != nil {
+		return maintenanceWindowError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":           "Maintenance window updated.",
+		"maintenanceWindow": window,
+	})
+}
+
+// @Tags Maintenance
+// @Accept json
+// @Produce json
+// @Param id path string true "Maintenance Window ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/maintenance/{id} [delete]
+func (h *Handler) DeleteMaintenanceWindow(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+	if err := h.app.DeleteMaintenanceWindow(id); err != nil {
+		return maintenanceWindowError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message": "Maintenance window deleted.",
+	})
+}
+
+func maintenanceWindowError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case svcerr.IsNotFound(err):
+		status = fiber.StatusNotFound
+	case errors.Is(err, svcerr.ErrMaintenanceScopeNotFound):
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/chamanbravo/upstat/internal/dto"
 	"github.com/chamanbravo/upstat/pkg"
+	"github.com/chamanbravo/upstat/svcerr"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -32,9 +34,7 @@ func (h *Handler) CreateMonitor(c *fiber.Ctx) error {
 
 	monitor, err := h.app.CreateMonitor(newMonitor)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": err.Error(),
-		})
+		return monitorError(c, err)
 	}
 
 	err = h.app.NotificationMonitor(monitor.ID, newMonitor.NotificationChannels)
@@ -80,7 +80,7 @@ func (h *Handler) MonitorInfo(c *fiber.Ctx) error {
 		})
 	}
 
-	monitor, err := h.app.FindMonitorById(id)
+	monitor, err := h.app.FindMonitorById(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -116,33 +116,38 @@ func (h *Handler) UpdateMonitor(c *fiber.Ctx) error {
 		})
 	}
 
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
 	errors := pkg.BodyValidator.Validate(monitor)
 	if len(errors) > 0 {
 		return c.Status(400).JSON(errors)
 	}
 
-	id, err := strconv.Atoi(idParam)
+	err = h.app.UpdateMonitorById(c.UserContext(), id, monitor)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Invalid ID parameter",
-		})
+		return monitorError(c, err)
 	}
 
-	err = h.app.UpdateMonitorById(id, monitor)
+	err = h.app.UpdateNotificationMonitorById(id, monitor.NotificationChannels)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
 		})
 	}
 
-	err = h.app.UpdateNotificationMonitorById(id, monitor.NotificationChannels)
+	err = h.app.UpdateStatusPageMonitorById(id, monitor.StatusPages)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
 		})
 	}
 
-	err = h.app.UpdateStatusPageMonitorById(id, monitor.StatusPages)
+	err = h.app.RescheduleMonitor(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -159,6 +164,7 @@ func (h *Handler) UpdateMonitor(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Monitor ID"
+// @Param body body dto.PauseMonitorIn false "Body"
 // @Success 200 {object} dto.SuccessResponse
 // @Success 400 {object} dto.ErrorResponse
 // @Router /api/monitors/{id}/pause [patch]
@@ -177,8 +183,23 @@ func (h *Handler) PauseMonitor(c *fiber.Ctx) error {
 		})
 	}
 
-	h.app.StopMonitoringProcess(id)
-	err = h.app.UpdateMonitorStatus(id, "yellow")
+	pause := new(dto.PauseMonitorIn)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(pause); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	if pause.ResumeAt != nil && !pause.ResumeAt.After(time.Now()) {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "resumeAt must be in the future",
+		})
+	}
+
+	username, _ := c.Locals("username").(string)
+	err = h.app.PauseMonitor(c.UserContext(), id, username, pause.ResumeAt)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -212,15 +233,7 @@ func (h *Handler) ResumeMonitor(c *fiber.Ctx) error {
 		})
 	}
 
-	monitor, err := h.app.FindMonitorById(id)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": err.Error(),
-		})
-	}
-
-	h.app.StartMonitoringProcess(monitor)
-	err = h.app.UpdateMonitorStatus(id, "green")
+	err = h.app.ResumeMonitor(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -238,7 +251,7 @@ func (h *Handler) ResumeMonitor(c *fiber.Ctx) error {
 // @Success 400 {object} dto.ErrorResponse
 // @Router /api/monitors [get]
 func (h *Handler) MonitorsList(c *fiber.Ctx) error {
-	monitors, err := h.app.RetrieveMonitors()
+	monitors, err := h.app.RetrieveMonitors(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -259,6 +272,10 @@ func (h *Handler) MonitorsList(c *fiber.Ctx) error {
 			"url":       v.Url,
 			"frequency": v.Frequency,
 			"status":    v.Status,
+			"active":    v.Active,
+			"pausedBy":  v.PausedBy,
+			"pausedAt":  v.PausedAt,
+			"resumeAt":  v.ResumeAt,
 			"heartbeat": heartbeat,
 		}
 		monitorsList = append(monitorsList, monitorItem)
@@ -319,6 +336,39 @@ func (h *Handler) RetrieveHeartbeat(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Monitor ID"
+// @Success 200 {object} dto.CheckMonitorOut
+// @Success 400 {object} dto.ErrorResponse
+// @Router /api/monitors/{id}/check [post]
+func (h *Handler) CheckMonitor(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	heartbeat, err := h.app.CheckMonitor(c.UserContext(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "success", "heartbeat": heartbeat})
+}
+
+// @Tags Monitors
+// @Accept json
+// @Produce json
+// @Param id path string true "Monitor ID"
+// @Param excludeMaintenance query bool false "Leave maintenance windows out of uptime"
 // @Success 200 {object} dto.MonitorSummaryOut
 // @Success 400 {object} dto.ErrorResponse
 // @Router /api/monitors/{id}/summary [get]
@@ -344,14 +394,30 @@ func (h *Handler) MonitorSummary(c *fiber.Ctx) error {
 		})
 	}
 
-	dayUptime, err := h.app.RetrieveUptime(id, time.Now().Add(-time.Hour*24))
+	excludeMaintenance := c.QueryBool("excludeMaintenance")
+
+	dayUptime, err := h.app.RetrieveUptime(id, time.Now().Add(-time.Hour*24), excludeMaintenance)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	monthUptime, err := h.app.RetrieveUptime(id, time.Now().Add(-time.Hour*30*24), excludeMaintenance)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	dayDegraded, err := h.app.RetrieveDegradedTime(id, time.Now().Add(-time.Hour*24), excludeMaintenance)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
 		})
 	}
 
-	monthUptime, err := h.app.RetrieveUptime(id, time.Now().Add(-time.Hour*30*24))
+	monthDegraded, err := h.app.RetrieveDegradedTime(id, time.Now().Add(-time.Hour*30*24), excludeMaintenance)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -364,6 +430,8 @@ func (h *Handler) MonitorSummary(c *fiber.Ctx) error {
 			"averageLatency": averageLatency,
 			"dayUptime":      dayUptime,
 			"monthUptime":    monthUptime,
+			"dayDegraded":    dayDegraded,
+			"monthDegraded":  monthDegraded,
 		},
 	})
 }
@@ -423,7 +491,7 @@ func (h *Handler) CertificateExpiryCountDown(c *fiber.Ctx) error {
 		})
 	}
 
-	monitor, err := h.app.FindMonitorById(id)
+	monitor, err := h.app.FindMonitorById(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -470,7 +538,7 @@ func (h *Handler) NotificationChannelListOfMonitor(c *fiber.Ctx) error {
 		})
 	}
 
-	notification, err := h.app.FindNotificationChannelsByMonitorId(id)
+	notification, err := h.app.FindNotificationChannelsByMonitorId(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -517,3 +585,17 @@ func (h *Handler) StatusPagesListOfMonitor(c *fiber.Ctx) error {
 		"statusPages": statusPages,
 	})
 }
+
+func monitorError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case svcerr.IsNotFound(err):
+		status = fiber.StatusNotFound
+	case errors.Is(err, svcerr.ErrMonitorAuthTokenMissing):
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/chamanbravo/upstat/internal/dto"
 	"github.com/chamanbravo/upstat/pkg"
+	"github.com/chamanbravo/upstat/pkg/alerts"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -26,6 +27,9 @@ func (h *Handler) CreateNotification(c *fiber.Ctx) error {
 	}
 
 	errors := pkg.BodyValidator.Validate(notificationChannel)
+	if len(errors) == 0 {
+		errors = validateNotificationData(notificationChannel)
+	}
 	if len(errors) > 0 {
 		return c.Status(400).JSON(errors)
 	}
@@ -120,6 +124,9 @@ func (h *Handler) UpdateNotificationChannel(c *fiber.Ctx) error {
 	}
 
 	errors := pkg.BodyValidator.Validate(notificationChannel)
+	if len(errors) == 0 {
+		errors = validateNotificationData(notificationChannel)
+	}
 	if len(errors) > 0 {
 		return c.Status(400).JSON(errors)
 	}
@@ -178,3 +185,148 @@ func (h *Handler) NotificationChannelInfo(c *fiber.Ctx) error {
 		"notification": notification,
 	})
 }
+
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param body body dto.NotificationCreateIn true "Body"
+// @Success 200 {object} dto.NotificationTestOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.NotificationTestOut
+// @Router /api/notifications/test [post]
+func (h *Handler) TestNotification(c *fiber.Ctx) error {
+	notificationChannel := new(dto.NotificationCreateIn)
+	if err := c.BodyParser(notificationChannel); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(notificationChannel)
+	if len(errors) == 0 {
+		errors = validateNotificationData(notificationChannel)
+	}
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	response, err := h.app.TestNotification(c.UserContext(), notificationChannel)
+	return testNotificationResponse(c, response, err)
+}
+
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification Channel ID"
+// @Success 200 {object} dto.NotificationTestOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.NotificationTestOut
+// @Router /api/notifications/{id}/test [post]
+func (h *Handler) TestNotificationChannel(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	notification, err := h.app.FindNotificationById(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	response, err := h.app.TestNotification(c.UserContext(), &dto.NotificationCreateIn{
+		Name:     notification.Name,
+		Provider: notification.Provider,
+		Data:     notification.Data,
+	})
+	return testNotificationResponse(c, response, err)
+}
+
+// testNotificationResponse reports what the provider answered to a test
+// notification, failed deliveries are reported as a bad gateway.
+func testNotificationResponse(c *fiber.Ctx, response *alerts.Response, err error) error {
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"message":  err.Error(),
+			"response": response,
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":  "Test notification sent.",
+		"response": response,
+	})
+}
+
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification Channel ID"
+// @Param limit query int false "Number of deliveries, newest first (default 50)"
+// @Success 200 {object} dto.NotificationDeliveriesOut
+// @Success 400 {object} dto.ErrorResponse
+// @Router /api/notifications/{id}/deliveries [get]
+func (h *Handler) NotificationDeliveries(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	query := new(dto.NotificationDeliveriesIn)
+	if err := c.QueryParser(query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(query)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	deliveries, err := h.app.RetrieveDeliveries(id, query.Limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":    "success",
+		"deliveries": deliveries,
+	})
+}
+
+// validateNotificationData decodes the data of the notification channel
+// into the config of its provider and validates it.
+func validateNotificationData(nc *dto.NotificationCreateIn) map[string]string {
+	notifier, err := alerts.New(nc.Provider, nc.Data)
+	if err != nil {
+		return map[string]string{"data": err.Error()}
+	}
+
+	return pkg.BodyValidator.Validate(notifier)
+}
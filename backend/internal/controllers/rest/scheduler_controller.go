@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.SchedulerStatsOut
+// @Router /api/scheduler/stats [get]
+func (h *Handler) SchedulerStats(c *fiber.Ctx) error {
+	return c.Status(200).JSON(fiber.Map{
+		"message": "success",
+		"stats":   h.app.SchedulerStats(),
+	})
+}
@@ -231,9 +231,13 @@ func (h *Handler) StatusSummary(c *fiber.Ctx) error {
 
 			value.Total++
 			value.Timestamp = dateKey
-			if v.Status == "green" {
+			switch v.Status {
+			case "green":
 				value.Up++
-			} else {
+			case "orange":
+				value.Up++
+				value.Degraded++
+			default:
 				value.Down++
 			}
 
@@ -261,9 +265,27 @@ func (h *Handler) StatusSummary(c *fiber.Ctx) error {
 		monitorsList = append(monitorsList, monitorItem)
 	}
 
+	activeIncidents, recentIncidents, err := h.app.StatusPageIncidents(slug)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	activeMaintenance, upcomingMaintenance, err := h.app.StatusPageMaintenance(slug)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
 	return c.JSON(fiber.Map{
-		"message":        "Status pages list",
-		"statusPageInfo": statusPageInfo,
-		"monitors":       monitorsList,
+		"message":             "Status pages list",
+		"statusPageInfo":      statusPageInfo,
+		"monitors":            monitorsList,
+		"activeIncidents":     activeIncidents,
+		"recentIncidents":     recentIncidents,
+		"activeMaintenance":   activeMaintenance,
+		"upcomingMaintenance": upcomingMaintenance,
 	})
 }
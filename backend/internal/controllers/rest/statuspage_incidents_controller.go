@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/pkg"
+	"github.com/chamanbravo/upstat/svcerr"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Tags Status Page Incidents
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateStatusPageIncidentIn true "Body"
+// @Success 200 {object} dto.StatusPageIncidentOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/status-page-incidents [post]
+func (h *Handler) CreateStatusPageIncident(c *fiber.Ctx) error {
+	body := new(dto.CreateStatusPageIncidentIn)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(body)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	username, _ := c.Locals("username").(string)
+	incident, err := h.app.CreateStatusPageIncident(body, username)
+	if err != nil {
+		return statusPageIncidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":  "Status page incident created.",
+		"incident": incident,
+	})
+}
+
+// @Tags Status Page Incidents
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.StatusPageIncidentsOut
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/status-page-incidents [get]
+func (h *Handler) ListStatusPageIncidents(c *fiber.Ctx) error {
+	incidents, err := h.app.ListStatusPageIncidents()
+	if err != nil {
+		return statusPageIncidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":   "success",
+		"incidents": incidents,
+	})
+}
+
+// @Tags Status Page Incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Status Page Incident ID"
+// @Success 200 {object} dto.StatusPageIncidentOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/status-page-incidents/{id} [get]
+func (h *Handler) StatusPageIncidentInfo(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	incident, err := h.app.FindStatusPageIncidentById(id)
+	if err != nil {
+		return statusPageIncidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":  "success",
+		"incident": incident,
+	})
+}
+
+// @Tags Status Page Incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Status Page Incident ID"
+// @Param body body dto.StatusPageIncidentIn true "Body"
+// @Success 200 {object} dto.StatusPageIncidentOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/status-page-incidents/{id} [patch]
+func (h *Handler) UpdateStatusPageIncident(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	body := new(dto.StatusPageIncidentIn)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(body)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	incident, err := h.app.UpdateStatusPageIncident(id, body)
+	if err != nil {
+		return statusPageIncidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message":  "Status page incident updated.",
+		"incident": incident,
+	})
+}
+
+// @Tags Status Page Incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Status Page Incident ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/status-page-incidents/{id} [delete]
+func (h *Handler) DeleteStatusPageIncident(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	if err := h.app.DeleteStatusPageIncident(id); err != nil {
+		return statusPageIncidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message": "Status page incident deleted.",
+	})
+}
+
+// @Tags Status Page Incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Status Page Incident ID"
+// @Param body body dto.StatusPageIncidentUpdateIn true "Body"
+// @Success 200 {object} dto.StatusPageIncidentUpdateOut
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/status-page-incidents/{id}/updates [post]
+func (h *Handler) AddStatusPageIncidentUpdate(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	if idParam == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "ID parameter is missing",
+		})
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"message": "Invalid ID parameter",
+		})
+	}
+
+	body := new(dto.StatusPageIncidentUpdateIn)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	errors := pkg.BodyValidator.Validate(body)
+	if len(errors) > 0 {
+		return c.Status(400).JSON(errors)
+	}
+
+	username, _ := c.Locals("username").(string)
+	update, err := h.app.AddStatusPageIncidentUpdate(id, body, username)
+	if err != nil {
+		return statusPageIncidentError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message": "Status page incident update added.",
+		"update":  update,
+	})
+}
+
+func statusPageIncidentError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case svcerr.IsNotFound(err):
+		status = fiber.StatusNotFound
+	case errors.Is(err, svcerr.ErrStatusPageIncidentPageNotFound):
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
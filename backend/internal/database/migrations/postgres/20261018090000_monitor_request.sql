@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors ADD COLUMN headers TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE monitors ADD COLUMN body TEXT NOT NULL DEFAULT '';
+ALTER TABLE monitors ADD COLUMN content_type VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE monitors ADD COLUMN auth_method VARCHAR(50) NOT NULL DEFAULT 'none';
+ALTER TABLE monitors ADD COLUMN auth_username TEXT NOT NULL DEFAULT '';
+ALTER TABLE monitors ADD COLUMN auth_password TEXT NOT NULL DEFAULT '';
+ALTER TABLE monitors ADD COLUMN auth_token TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors DROP COLUMN headers;
+ALTER TABLE monitors DROP COLUMN body;
+ALTER TABLE monitors DROP COLUMN content_type;
+ALTER TABLE monitors DROP COLUMN auth_method;
+ALTER TABLE monitors DROP COLUMN auth_username;
+ALTER TABLE monitors DROP COLUMN auth_password;
+ALTER TABLE monitors DROP COLUMN auth_token;
+-- +goose StatementEnd
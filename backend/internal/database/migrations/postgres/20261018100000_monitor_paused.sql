@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors ADD COLUMN active BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE monitors ADD COLUMN paused_by VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE monitors ADD COLUMN paused_at TIMESTAMP;
+ALTER TABLE monitors ADD COLUMN resume_at TIMESTAMP;
+
+UPDATE monitors
+SET active = false,
+    paused_at = CURRENT_TIMESTAMP,
+    status = COALESCE((SELECT h.status FROM heartbeats h WHERE h.monitor_id = monitors.id ORDER BY h.timestamp DESC LIMIT 1), 'green')
+WHERE status = 'yellow';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE monitors SET status = 'yellow' WHERE active = false;
+
+ALTER TABLE monitors DROP COLUMN active;
+ALTER TABLE monitors DROP COLUMN paused_by;
+ALTER TABLE monitors DROP COLUMN paused_at;
+ALTER TABLE monitors DROP COLUMN resume_at;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notifications ADD COLUMN remind_interval INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN remind_max INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notification_outbox ADD COLUMN incident_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notification_outbox ADD COLUMN reminder INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX notification_outbox_incident_idx ON notification_outbox(incident_id, notification_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX notification_outbox_incident_idx;
+ALTER TABLE notification_outbox DROP COLUMN reminder;
+ALTER TABLE notification_outbox DROP COLUMN incident_id;
+ALTER TABLE notifications DROP COLUMN remind_max;
+ALTER TABLE notifications DROP COLUMN remind_interval;
+-- +goose StatementEnd
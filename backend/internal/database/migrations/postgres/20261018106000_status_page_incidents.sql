@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE status_page_incidents (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    severity VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP
+);
+
+CREATE TABLE status_page_incidents_status_pages (
+    incident_id INTEGER REFERENCES status_page_incidents(id) ON DELETE CASCADE NOT NULL,
+    status_pages_id INTEGER REFERENCES status_pages(id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (incident_id, status_pages_id)
+);
+
+CREATE TABLE status_page_incident_updates (
+    id SERIAL PRIMARY KEY,
+    incident_id INTEGER REFERENCES status_page_incidents(id) ON DELETE CASCADE NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX status_page_incident_updates_incident_idx ON status_page_incident_updates(incident_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE status_page_incident_updates;
+DROP TABLE status_page_incidents_status_pages;
+DROP TABLE status_page_incidents;
+-- +goose StatementEnd
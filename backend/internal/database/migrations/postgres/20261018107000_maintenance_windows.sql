@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE maintenance_windows (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    recurrence VARCHAR(8) NOT NULL DEFAULT '',
+    rule VARCHAR(255) NOT NULL DEFAULT '',
+    duration INTEGER NOT NULL DEFAULT 0,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE maintenance_windows_monitors (
+    maintenance_id INTEGER REFERENCES maintenance_windows(id) ON DELETE CASCADE NOT NULL,
+    monitor_id INTEGER REFERENCES monitors(id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (maintenance_id, monitor_id)
+);
+
+CREATE TABLE maintenance_windows_status_pages (
+    maintenance_id INTEGER REFERENCES maintenance_windows(id) ON DELETE CASCADE NOT NULL,
+    status_pages_id INTEGER REFERENCES status_pages(id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (maintenance_id, status_pages_id)
+);
+
+-- Heartbeats and incidents keep the window they happened in, heartbeats
+-- even after it is deleted so that uptime history doesn't change.
+ALTER TABLE heartbeats ADD COLUMN maintenance_id INTEGER;
+ALTER TABLE incidents ADD COLUMN maintenance_id INTEGER REFERENCES maintenance_windows(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE incidents DROP COLUMN maintenance_id;
+ALTER TABLE heartbeats DROP COLUMN maintenance_id;
+DROP TABLE maintenance_windows_status_pages;
+DROP TABLE maintenance_windows_monitors;
+DROP TABLE maintenance_windows;
+-- +goose StatementEnd
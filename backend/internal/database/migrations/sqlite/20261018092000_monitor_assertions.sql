@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors ADD COLUMN accepted_status_codes TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE monitors ADD COLUMN keyword TEXT NOT NULL DEFAULT '';
+ALTER TABLE monitors ADD COLUMN keyword_regex BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE monitors ADD COLUMN keyword_invert BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE monitors ADD COLUMN max_body_size INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors DROP COLUMN accepted_status_codes;
+ALTER TABLE monitors DROP COLUMN keyword;
+ALTER TABLE monitors DROP COLUMN keyword_regex;
+ALTER TABLE monitors DROP COLUMN keyword_invert;
+ALTER TABLE monitors DROP COLUMN max_body_size;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors ADD COLUMN retries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD COLUMN retry_interval INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors DROP COLUMN retries;
+ALTER TABLE monitors DROP COLUMN retry_interval;
+-- +goose StatementEnd
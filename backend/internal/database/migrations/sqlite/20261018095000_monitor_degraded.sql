@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors ADD COLUMN latency_threshold INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD COLUMN degraded_after INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors DROP COLUMN latency_threshold;
+ALTER TABLE monitors DROP COLUMN degraded_after;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE incidents RENAME COLUMN created_at TO started_at;
+ALTER TABLE incidents RENAME COLUMN description TO cause;
+ALTER TABLE incidents ADD COLUMN resolved_at TIMESTAMP;
+ALTER TABLE incidents ADD COLUMN heartbeat_id INTEGER;
+
+-- Every status flip used to be its own row, so an incident ended when the
+-- next row of its monitor was written. Rows from before incidents had
+-- timestamps are closed at the time of the migration.
+UPDATE incidents SET resolved_at = COALESCE(
+    (SELECT n.started_at FROM incidents n WHERE n.monitor_id = incidents.monitor_id AND n.id > incidents.id ORDER BY n.id LIMIT 1),
+    CURRENT_TIMESTAMP
+)
+WHERE type <> 'UP'
+    AND EXISTS (SELECT 1 FROM incidents n WHERE n.monitor_id = incidents.monitor_id AND n.id > incidents.id);
+
+DELETE FROM incidents WHERE type = 'UP';
+
+ALTER TABLE incidents DROP COLUMN is_positive;
+
+CREATE INDEX incidents_monitor_idx ON incidents(monitor_id, started_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX incidents_monitor_idx;
+ALTER TABLE incidents ADD COLUMN is_positive BOOLEAN DEFAULT false;
+ALTER TABLE incidents DROP COLUMN heartbeat_id;
+ALTER TABLE incidents DROP COLUMN resolved_at;
+ALTER TABLE incidents RENAME COLUMN cause TO description;
+ALTER TABLE incidents RENAME COLUMN started_at TO created_at;
+-- +goose StatementEnd
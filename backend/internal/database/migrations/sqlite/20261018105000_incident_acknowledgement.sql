@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE incidents ADD COLUMN acknowledged_at TIMESTAMP;
+ALTER TABLE incidents ADD COLUMN acknowledged_by VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE incident_notes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    incident_id INTEGER REFERENCES incidents(id) ON DELETE CASCADE NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX incident_notes_incident_idx ON incident_notes(incident_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE incident_notes;
+ALTER TABLE incidents DROP COLUMN acknowledged_by;
+ALTER TABLE incidents DROP COLUMN acknowledged_at;
+-- +goose StatementEnd
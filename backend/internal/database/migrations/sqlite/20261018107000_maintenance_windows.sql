@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE maintenance_windows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    recurrence VARCHAR(8) NOT NULL DEFAULT '',
+    rule VARCHAR(255) NOT NULL DEFAULT '',
+    duration INTEGER NOT NULL DEFAULT 0,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE maintenance_windows_monitors (
+    maintenance_id INTEGER REFERENCES maintenance_windows(id) ON DELETE CASCADE NOT NULL,
+    monitor_id INTEGER REFERENCES monitors(id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (maintenance_id, monitor_id)
+);
+
+CREATE TABLE maintenance_windows_status_pages (
+    maintenance_id INTEGER REFERENCES maintenance_windows(id) ON DELETE CASCADE NOT NULL,
+    status_pages_id INTEGER REFERENCES status_pages(id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (maintenance_id, status_pages_id)
+);
+
+-- Heartbeats and incidents keep the window they happened in, heartbeats
+-- even after it is deleted so that uptime history doesn't change.
+ALTER TABLE heartbeats ADD COLUMN maintenance_id INTEGER;
+ALTER TABLE incidents ADD COLUMN maintenance_id INTEGER REFERENCES maintenance_windows(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The table is rebuilt without the column, as its foreign key keeps some
+-- SQLite versions from dropping it.
+CREATE TABLE incidents_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type VARCHAR(50) NOT NULL,
+    cause TEXT NOT NULL,
+    monitor_id INTEGER REFERENCES monitors(id) ON DELETE CASCADE NOT NULL,
+    started_at TIMESTAMP,
+    resolved_at TIMESTAMP,
+    heartbeat_id INTEGER,
+    acknowledged_at TIMESTAMP,
+    acknowledged_by VARCHAR(255) NOT NULL DEFAULT ''
+);
+INSERT INTO incidents_new
+SELECT id, type, cause, monitor_id, started_at, resolved_at, heartbeat_id, acknowledged_at, acknowledged_by
+FROM incidents;
+DROP TABLE incidents;
+ALTER TABLE incidents_new RENAME TO incidents;
+CREATE INDEX incidents_monitor_idx ON incidents(monitor_id, started_at);
+
+ALTER TABLE heartbeats DROP COLUMN maintenance_id;
+DROP TABLE maintenance_windows_status_pages;
+DROP TABLE maintenance_windows_monitors;
+DROP TABLE maintenance_windows;
+-- +goose StatementEnd
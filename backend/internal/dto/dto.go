@@ -1,9 +1,11 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/alerts"
 )
 
 type SuccessResponse struct {
@@ -38,13 +40,40 @@ type UserSignInOut struct {
 }
 
 type AddMonitorIn struct {
-	Name                 string   `json:"name" validate:"required"`
-	URL                  string   `json:"url" validate:"required"`
-	Type                 string   `json:"type" validate:"required"`
-	Frequency            int      `json:"frequency" validate:"required"`
-	Method               string   `json:"method" validate:"required"`
-	NotificationChannels []string `json:"notificationChannels"`
-	StatusPages          []string `json:"statusPages"`
+	Name                 string                 `json:"name" validate:"required"`
+	URL                  string                 `json:"url" validate:"required"`
+	Type                 string                 `json:"type" validate:"required,monitortype"`
+	Frequency            int                    `json:"frequency" validate:"required"`
+	Method               string                 `json:"method" validate:"required_if=Type http"`
+	Timeout              int                    `json:"timeout" validate:"min=0"`
+	Retries              int                    `json:"retries" validate:"min=0"`
+	RetryInterval        int                    `json:"retryInterval" validate:"min=0"`
+	LatencyThreshold     int                    `json:"latencyThreshold" validate:"min=0"`
+	DegradedAfter        int                    `json:"degradedAfter" validate:"min=0"`
+	Headers              map[string]string      `json:"headers"`
+	Body                 string                 `json:"body"`
+	ContentType          string                 `json:"contentType"`
+	AuthMethod           string                 `json:"authMethod" validate:"omitempty,oneof=none basic bearer"`
+	AuthUsername         string                 `json:"authUsername" validate:"required_if=AuthMethod basic"`
+	AuthPassword         string                 `json:"authPassword"`
+	AuthToken            string                 `json:"authToken"`
+	AcceptedStatusCodes  []string               `json:"acceptedStatusCodes" validate:"dive,statuscode"`
+	Keyword              string                 `json:"keyword"`
+	KeywordRegex         bool                   `json:"keywordRegex"`
+	KeywordInvert        bool                   `json:"keywordInvert"`
+	MaxBodySize          int                    `json:"maxBodySize" validate:"min=0"`
+	JsonAssertions       []models.JsonAssertion `json:"jsonAssertions" validate:"dive"`
+	FollowRedirects      *bool                  `json:"followRedirects"`
+	NotificationChannels []string               `json:"notificationChannels"`
+	StatusPages          []string               `json:"statusPages"`
+	// ClearAuthPassword and ClearAuthToken remove the saved credentials on
+	// update, which are kept otherwise when none are given.
+	ClearAuthPassword bool `json:"clearAuthPassword"`
+	ClearAuthToken    bool `json:"clearAuthToken"`
+}
+
+type PauseMonitorIn struct {
+	ResumeAt *time.Time `json:"resumeAt"`
 }
 
 type UpdatePasswordIn struct {
@@ -68,6 +97,10 @@ type MonitorItem struct {
 	URL        string             `json:"url"`
 	Frequency  int                `json:"frequency"`
 	Status     string             `json:"status"`
+	Active     bool               `json:"active"`
+	PausedBy   string             `json:"pausedBy"`
+	PausedAt   *time.Time         `json:"pausedAt"`
+	ResumeAt   *time.Time         `json:"resumeAt"`
 	Heartbeats []models.Heartbeat `json:"heartbeat"`
 }
 
@@ -85,6 +118,11 @@ type MonitorInfoOut struct {
 	Monitor models.Monitor `json:"monitor"`
 }
 
+type CheckMonitorOut struct {
+	SuccessResponse
+	Heartbeat models.Heartbeat `json:"heartbeat"`
+}
+
 type RetrieveHeartbeatIn struct {
 	StartTime time.Time `query:"startTime"`
 }
@@ -93,6 +131,8 @@ type MonitorSummary struct {
 	AverageLatency float64 `json:"averageLatency"`
 	DayUptime      float64 `json:"dayUptime"`
 	MonthUptime    float64 `json:"monthUptime"`
+	DayDegraded    float64 `json:"dayDegraded"`
+	MonthDegraded  float64 `json:"monthDegraded"`
 }
 
 type MonitorSummaryOut struct {
@@ -100,25 +140,37 @@ type MonitorSummaryOut struct {
 	Summary MonitorSummary `json:"summary"`
 }
 
-type CertificateExpiryCountDown struct {
+type SchedulerStats struct {
+	Workers    int   `json:"workers"`
+	Scheduled  int   `json:"scheduled"`
+	Running    int   `json:"running"`
+	QueueDepth int   `json:"queueDepth"`
+	Lag        int64 `json:"lag"`
+}
+
+type SchedulerStatsOut struct {
 	SuccessResponse
-	DaysUntilExpiration int `json:"daysUntilExpiration"`
+	Stats SchedulerStats `json:"stats"`
 }
 
-type NotificationData struct {
-	WebhookUrl string `json:"webhookUrl"`
+type CertificateExpiryCountDown struct {
+	SuccessResponse
+	DaysUntilExpiration int `json:"daysUntilExpiration"`
 }
 
 type NotificationCreateIn struct {
-	Name     string           `json:"name" validate:"required"`
-	Provider string           `json:"provider" validate:"oneof=Discord"`
-	Data     NotificationData `json:"data"`
+	Name     string          `json:"name" validate:"required"`
+	Provider string          `json:"provider" validate:"required,notificationprovider"`
+	Data     json.RawMessage `json:"data" validate:"required" swaggertype:"object"`
+	// RemindInterval is in minutes, 0 disables reminders.
+	RemindInterval int `json:"remindInterval" validate:"min=0"`
+	RemindMax      int `json:"remindMax" validate:"min=0"`
 }
 
 type NotificationItem struct {
 	ID       string `json:"id"`
 	Name     string `json:"name" validate:"required"`
-	Provider string `json:"provider" validate:"oneof=Discord"`
+	Provider string `json:"provider"`
 }
 
 type NotificationListOut struct {
@@ -131,11 +183,58 @@ type NotificationChannelInfo struct {
 	Notification models.Notification `json:"notification"`
 }
 
+type NotificationTestOut struct {
+	SuccessResponse
+	Response *alerts.Response `json:"response"`
+}
+
+type NotificationDeliveriesIn struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+type NotificationDeliveriesOut struct {
+	SuccessResponse
+	Deliveries []models.NotificationDelivery `json:"deliveries"`
+}
+
 type CreateStatusPageIn struct {
 	Name string `json:"name"`
 	Slug string `json:"slug"`
 }
 
+type StatusPageIncidentIn struct {
+	Title       string   `json:"title" validate:"required,max=255"`
+	Severity    string   `json:"severity" validate:"required,oneof=minor major critical"`
+	StatusPages []string `json:"statusPages" validate:"required,min=1"`
+}
+
+type StatusPageIncidentUpdateIn struct {
+	Status  string `json:"status" validate:"required,oneof=investigating identified monitoring resolved"`
+	Message string `json:"message" validate:"required"`
+}
+
+// CreateStatusPageIncidentIn is a new incident along with the first
+// update of its timeline.
+type CreateStatusPageIncidentIn struct {
+	StatusPageIncidentIn
+	StatusPageIncidentUpdateIn
+}
+
+type StatusPageIncidentOut struct {
+	SuccessResponse
+	Incident *models.StatusPageIncident `json:"incident"`
+}
+
+type StatusPageIncidentsOut struct {
+	SuccessResponse
+	Incidents []*models.StatusPageIncident `json:"incidents"`
+}
+
+type StatusPageIncidentUpdateOut struct {
+	SuccessResponse
+	Update *models.StatusPageIncidentUpdate `json:"update"`
+}
+
 type StatusPageInfo struct {
 	SuccessResponse
 	StatusPage models.StatusPage `json:"statusPage"`
@@ -146,17 +245,63 @@ type ListStatusPagesOut struct {
 	StatusPages []models.StatusPage `json:"statusPages"`
 }
 
+type IncidentsIn struct {
+	MonitorId int    `query:"monitorId" validate:"min=0"`
+	Status    string `query:"status" validate:"omitempty,oneof=open resolved"`
+	Type      string `query:"type" validate:"omitempty,oneof=DOWN DEGRADED"`
+	// From and To select the incidents that were open at some point
+	// within the range.
+	From   time.Time `query:"from"`
+	To     time.Time `query:"to"`
+	Cursor int       `query:"cursor" validate:"min=0"`
+	Limit  int       `query:"limit" validate:"omitempty,min=1,max=500"`
+	Sort   string    `query:"sort" validate:"omitempty,oneof=asc desc"`
+}
+
+type IncidentsOut struct {
+	SuccessResponse
+	Incidents []*models.Incident `json:"incidents"`
+	// NextCursor is passed as cursor to get the next page, it is 0 on
+	// the last page.
+	NextCursor int `json:"nextCursor"`
+}
+
+type IncidentInfo struct {
+	SuccessResponse
+	Incident   *models.Incident       `json:"incident"`
+	Notes      []*models.IncidentNote `json:"notes"`
+	Heartbeats []*models.Heartbeat    `json:"heartbeats"`
+}
+
+type IncidentOut struct {
+	SuccessResponse
+	Incident *models.Incident `json:"incident"`
+}
+
+type IncidentNoteIn struct {
+	Text string `json:"text" validate:"required,max=5000"`
+}
+
+type IncidentNoteOut struct {
+	SuccessResponse
+	Note *models.IncidentNote `json:"note"`
+}
+
 type SaveIncident struct {
-	Type        string `json:"type"`
-	Description string `json:"description"`
-	IsPositive  bool   `json:"is_positive"`
-	MonitorId   int    `json:"monitor_id"`
+	Type        string    `json:"type"`
+	Cause       string    `json:"cause"`
+	MonitorId   int       `json:"monitor_id"`
+	HeartbeatId int       `json:"heartbeat_id"`
+	StartedAt   time.Time `json:"started_at"`
+	// MaintenanceId is the window the incident started in, if any.
+	MaintenanceId *int `json:"maintenance_id"`
 }
 
 type HeartbeatSummary struct {
 	Timestamp string `json:"timestamp"`
 	Total     int    `json:"total"`
 	Up        int    `json:"up"`
+	Degraded  int    `json:"degraded"`
 	Down      int    `json:"down"`
 }
 
@@ -171,4 +316,44 @@ type StatusPageSummary struct {
 	SuccessResponse
 	StatusPageInfo models.StatusPage          `json:"statusPageInfo"`
 	Monitors       []StatusPageMonitorSummary `json:"monitors"`
+	// ActiveIncidents are still open, RecentIncidents were resolved
+	// within the last week.
+	ActiveIncidents []*models.StatusPageIncident `json:"activeIncidents"`
+	RecentIncidents []*models.StatusPageIncident `json:"recentIncidents"`
+	// UpcomingMaintenance starts within the next week.
+	ActiveMaintenance   []*MaintenanceOccurrence `json:"activeMaintenance"`
+	UpcomingMaintenance []*MaintenanceOccurrence `json:"upcomingMaintenance"`
+}
+
+type MaintenanceWindowIn struct {
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description"`
+	StartsAt    time.Time  `json:"startsAt" validate:"required"`
+	EndsAt      *time.Time `json:"endsAt" validate:"required_without=Recurrence,omitempty,gtfield=StartsAt"`
+	Recurrence  string     `json:"recurrence" validate:"omitempty,oneof=cron rrule"`
+	Rule        string     `json:"rule" validate:"required_with=Recurrence,recurrencerule"`
+	// Duration of every occurrence of a recurring window, in minutes.
+	Duration    int      `json:"duration" validate:"required_with=Recurrence,min=0"`
+	Timezone    string   `json:"timezone" validate:"omitempty,timezone"`
+	Monitors    []string `json:"monitors" validate:"required_without=StatusPages,dive,numeric"`
+	StatusPages []string `json:"statusPages" validate:"dive,numeric"`
+}
+
+type MaintenanceWindowOut struct {
+	SuccessResponse
+	MaintenanceWindow *models.MaintenanceWindow `json:"maintenanceWindow"`
+}
+
+type MaintenanceWindowsOut struct {
+	SuccessResponse
+	MaintenanceWindows []*models.MaintenanceWindow `json:"maintenanceWindows"`
+}
+
+// MaintenanceOccurrence is a single occurrence of a maintenance window.
+type MaintenanceOccurrence struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartsAt    time.Time `json:"startsAt"`
+	EndsAt      time.Time `json:"endsAt"`
 }
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEntry is an alert waiting to be delivered to a notification
+// channel, along with the channel's current provider and config.
+type OutboxEntry struct {
+	ID             int `json:"id"`
+	NotificationId int `json:"notificationId"`
+	IncidentId     int `json:"incidentId"`
+	// Reminder numbers the reminders sent for an incident, 0 is the
+	// alert sent when the incident was opened.
+	Reminder      int             `json:"reminder"`
+	Provider      string          `json:"provider"`
+	Data          json.RawMessage `json:"data"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt"`
+	LastError     string          `json:"lastError"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// ReminderState is how often a notification channel has been reminded of
+// an open incident.
+type ReminderState struct {
+	NotificationId int
+	RemindInterval int
+	RemindMax      int
+	Reminders      int
+}
+
+type NotificationDelivery struct {
+	ID             int       `json:"id"`
+	NotificationId int       `json:"notificationId"`
+	OutboxId       int       `json:"outboxId"`
+	Type           string    `json:"type"`
+	Attempt        int       `json:"attempt"`
+	StatusCode     int       `json:"statusCode"`
+	Error          string    `json:"error"`
+	Delivered      bool      `json:"delivered"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
@@ -10,4 +10,6 @@ type Heartbeat struct {
 	Status     string    `json:"status"`
 	Latency    int       `json:"latency"`
 	Message    string    `json:"message"`
+	// MaintenanceId is the window the check ran in, if any.
+	MaintenanceId *int `json:"maintenance_id"`
 }
@@ -1,9 +1,32 @@
 package models
 
+import "time"
+
+// Incident is a period in which a monitor was down or degraded. It stays
+// open until the monitor recovers or changes to another failing status.
 type Incident struct {
-	ID          int    `json:"id"`
-	Type        string `json:"type"`
-	Description string `json:"description"`
-	IsPositive  bool   `json:"is_positive"`
-	MonitorId   int    `json:"monitor_id"`
+	ID          int        `json:"id"`
+	Type        string     `json:"type"`
+	Cause       string     `json:"cause"`
+	MonitorId   int        `json:"monitor_id"`
+	HeartbeatId *int       `json:"heartbeat_id"`
+	StartedAt   *time.Time `json:"started_at"`
+	ResolvedAt  *time.Time `json:"resolved_at"`
+	// Duration is in seconds, for open incidents up to now.
+	Duration int64 `json:"duration"`
+	// AcknowledgedAt is set once someone is handling the incident, no
+	// more reminders are sent about it from then on.
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	AcknowledgedBy string     `json:"acknowledged_by"`
+	// MaintenanceId is set when the incident started during a maintenance
+	// window, no alerts are sent about it.
+	MaintenanceId *int `json:"maintenance_id"`
+}
+
+type IncidentNote struct {
+	ID         int       `json:"id"`
+	IncidentId int       `json:"incident_id"`
+	Username   string    `json:"username"`
+	Text       string    `json:"text"`
+	CreatedAt  time.Time `json:"created_at"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// MaintenanceWindow is a period of planned work on the monitors it is
+// scoped to, directly or through their status pages. A one-off window
+// lasts from StartsAt to EndsAt. A recurring one has occurrences lasting
+// Duration minutes that start as its cron expression or RRULE says, from
+// StartsAt until EndsAt if set.
+type MaintenanceWindow struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	StartsAt    time.Time  `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	// Recurrence is empty for one-off windows, "cron" or "rrule" otherwise.
+	Recurrence  string    `json:"recurrence"`
+	Rule        string    `json:"rule"`
+	Duration    int       `json:"duration"`
+	Timezone    string    `json:"timezone"`
+	Monitors    []int     `json:"monitors"`
+	StatusPages []int     `json:"statusPages"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
@@ -1,11 +1,59 @@
 package models
 
+import "time"
+
+type JsonAssertion struct {
+	Path     string `json:"path" validate:"required"`
+	Operator string `json:"operator" validate:"oneof=exists eq ne contains gt gte lt lte"`
+	Value    string `json:"value"`
+}
+
 type Monitor struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	Url       string `json:"url"`
-	Type      string `json:"type"`
-	Method    string `json:"method"`
-	Frequency int    `json:"frequency"`
-	Status    string `json:"status"`
+	ID               int               `json:"id"`
+	Name             string            `json:"name"`
+	Url              string            `json:"url"`
+	Type             string            `json:"type"`
+	Method           string            `json:"method"`
+	Frequency        int               `json:"frequency"`
+	Status           string            `json:"status"`
+	Active           bool              `json:"active"`
+	PausedBy         string            `json:"pausedBy"`
+	PausedAt         *time.Time        `json:"pausedAt"`
+	ResumeAt         *time.Time        `json:"resumeAt"`
+	Timeout          int               `json:"timeout"`
+	Retries          int               `json:"retries"`
+	RetryInterval    int               `json:"retryInterval"`
+	LatencyThreshold int               `json:"latencyThreshold"`
+	DegradedAfter    int               `json:"degradedAfter"`
+	Headers          map[string]string `json:"headers"`
+	Body             string            `json:"body"`
+	ContentType      string            `json:"contentType"`
+	AuthMethod       string            `json:"authMethod"`
+	AuthUsername     string            `json:"authUsername"`
+	// Credentials are never sent back to clients.
+	AuthPassword        string          `json:"-"`
+	AuthToken           string          `json:"-"`
+	AcceptedStatusCodes []string        `json:"acceptedStatusCodes"`
+	Keyword             string          `json:"keyword"`
+	KeywordRegex        bool            `json:"keywordRegex"`
+	KeywordInvert       bool            `json:"keywordInvert"`
+	MaxBodySize         int             `json:"maxBodySize"`
+	JsonAssertions      []JsonAssertion `json:"jsonAssertions"`
+	// FollowRedirects is off to check the redirect response itself.
+	FollowRedirects bool `json:"followRedirects"`
+}
+
+// Redacted returns a copy of the monitor without its credentials, to be
+// handed to notification channels. Custom headers often carry API keys, so
+// only their names are kept.
+func (m *Monitor) Redacted() *Monitor {
+	redacted := *m
+	redacted.AuthPassword, redacted.AuthToken = "", ""
+	if m.Headers != nil {
+		redacted.Headers = make(map[string]string, len(m.Headers))
+		for name := range m.Headers {
+			redacted.Headers[name] = "[redacted]"
+		}
+	}
+	return &redacted
 }
@@ -1,12 +1,15 @@
 package models
 
-type NotificationData struct {
-	WebhookUrl string `json:"webhookUrl"`
-}
+import "encoding/json"
 
 type Notification struct {
-	ID       string           `json:"id"`
-	Name     string           `json:"name"`
-	Provider string           `json:"provider"`
-	Data     NotificationData `json:"data"`
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Provider string          `json:"provider"`
+	Data     json.RawMessage `json:"data" swaggertype:"object"`
+	// RemindInterval is how many minutes apart reminders are sent while
+	// an incident stays open, 0 disables reminders.
+	RemindInterval int `json:"remindInterval"`
+	// RemindMax caps the number of reminders per incident, 0 means no cap.
+	RemindMax int `json:"remindMax"`
 }
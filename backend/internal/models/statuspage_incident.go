@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// StatusPageIncident is written by hand to keep the visitors of status
+// pages informed, unlike the incidents that monitors open. Its Status is
+// the status of the latest update.
+type StatusPageIncident struct {
+	ID          int                         `json:"id"`
+	Title       string                      `json:"title"`
+	Severity    string                      `json:"severity"`
+	Status      string                      `json:"status"`
+	StatusPages []int                       `json:"statusPages"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+	ResolvedAt  *time.Time                  `json:"resolvedAt"`
+	Updates     []*StatusPageIncidentUpdate `json:"updates"`
+}
+
+type StatusPageIncidentUpdate struct {
+	ID         int       `json:"id"`
+	IncidentId int       `json:"incidentId"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message"`
+	Username   string    `json:"username"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func (r *Repository) EnqueueNotification(ctx context.Context, entry *models.OutboxEntry) error {
+	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO notification_outbox(notification_id, incident_id, reminder, type, payload, next_attempt_at, created_at) VALUES($1, $2, $3, $4, $5, $6, $7)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	_, err = stmt.ExecContext(ctx, entry.NotificationId, entry.IncidentId, entry.Reminder, entry.Type, string(entry.Payload), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	return nil
+}
+
+// DueNotifications returns pending outbox entries that are due, skipping
+// entries queued behind an older pending entry of the same channel so
+// that every channel receives its alerts in order.
+func (r *Repository) DueNotifications(ctx context.Context, now time.Time, limit int) ([]*models.OutboxEntry, error) {
+	stmt, err := r.db.PrepareContext(ctx, `
+	SELECT
+		o.id, o.notification_id, o.incident_id, o.reminder, n.provider, CAST(n.data AS TEXT), o.type, o.payload,
+		o.status, o.attempts, o.next_attempt_at, o.last_error, o.created_at
+	FROM
+		notification_outbox o
+	JOIN
+		notifications n ON o.notification_id = n.id
+	WHERE
+		o.status = 'pending'
+		AND o.next_attempt_at <= $1
+		AND NOT EXISTS (
+			SELECT 1 FROM notification_outbox p
+			WHERE p.notification_id = o.notification_id AND p.status = 'pending' AND p.id < o.id
+		)
+	ORDER BY o.id
+	LIMIT $2
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.OutboxEntry
+	for rows.Next() {
+		entry := new(models.OutboxEntry)
+		var data, payload string
+		err = rows.Scan(
+			&entry.ID, &entry.NotificationId, &entry.IncidentId, &entry.Reminder, &entry.Provider, &data, &entry.Type, &payload,
+			&entry.Status, &entry.Attempts, &entry.NextAttemptAt, &entry.LastError, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row of notification outbox: %w", err)
+		}
+		entry.Data = json.RawMessage(data)
+		entry.Payload = json.RawMessage(payload)
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// ReminderStates returns the notification channels of the monitor that
+// send reminders, along with the last reminder queued for the incident.
+func (r *Repository) ReminderStates(ctx context.Context, monitorId, incidentId int) ([]*models.ReminderState, error) {
+	stmt, err := r.db.PrepareContext(ctx, `
+	SELECT
+		n.id, n.remind_interval, n.remind_max, COALESCE(MAX(o.reminder), 0)
+	FROM
+		notifications_monitors nm
+	JOIN
+		notifications n ON nm.notification_id = n.id
+	LEFT JOIN
+		notification_outbox o ON o.notification_id = n.id AND o.incident_id = $2
+	WHERE
+		nm.monitor_id = $1 AND n.remind_interval > 0
+	GROUP BY
+		n.id, n.remind_interval, n.remind_max
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, monitorId, incidentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []*models.ReminderState
+	for rows.Next() {
+		state := new(models.ReminderState)
+		err = rows.Scan(&state.NotificationId, &state.RemindInterval, &state.RemindMax, &state.Reminders)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row of reminder states: %w", err)
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func (r *Repository) UpdateOutboxEntry(ctx context.Context, entry *models.OutboxEntry) error {
+	stmt, err := r.db.PrepareContext(ctx, "UPDATE notification_outbox SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4 WHERE id = $5")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, entry.Status, entry.Attempts, entry.NextAttemptAt.UTC(), entry.LastError, entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update notification outbox: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) SaveDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO notification_deliveries(notification_id, outbox_id, attempt, status_code, error, delivered, created_at) VALUES($1, $2, $3, $4, $5, $6, $7)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, delivery.NotificationId, delivery.OutboxId, delivery.Attempt, delivery.StatusCode, delivery.Error, delivery.Delivered, delivery.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save notification delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RetrieveDeliveries(notificationId, limit int) ([]*models.NotificationDelivery, error) {
+	stmt, err := r.db.Prepare(`
+	SELECT
+		d.id, d.notification_id, d.outbox_id, o.type, d.attempt, d.status_code, d.error, d.delivered, d.created_at
+	FROM
+		notification_deliveries d
+	JOIN
+		notification_outbox o ON d.outbox_id = o.id
+	WHERE
+		d.notification_id = $1
+	ORDER BY d.id DESC
+	LIMIT $2
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(notificationId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.NotificationDelivery, 0)
+	for rows.Next() {
+		delivery := new(models.NotificationDelivery)
+		err = rows.Scan(
+			&delivery.ID, &delivery.NotificationId, &delivery.OutboxId, &delivery.Type, &delivery.Attempt,
+			&delivery.StatusCode, &delivery.Error, &delivery.Delivered, &delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row of notification deliveries: %w", err)
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
@@ -1,14 +1,17 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/chamanbravo/upstat/internal/models"
 )
 
+const heartbeatColumns = "id, monitor_id, timestamp, status_code, status, latency, message, maintenance_id"
+
 func (r *Repository) RetrieveHeartbeats(id, limit int) ([]*models.Heartbeat, error) {
-	stmt, err := r.db.Prepare("SELECT * FROM heartbeats WHERE monitor_id = $1 ORDER BY timestamp DESC limit $2")
+	stmt, err := r.db.Prepare("SELECT " + heartbeatColumns + " FROM heartbeats WHERE monitor_id = $1 ORDER BY timestamp DESC limit $2")
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +27,7 @@ func (r *Repository) RetrieveHeartbeats(id, limit int) ([]*models.Heartbeat, err
 
 	for rows.Next() {
 		heartbeat := new(models.Heartbeat)
-		err := rows.Scan(&heartbeat.ID, &heartbeat.MonitorId, &heartbeat.Timestamp, &heartbeat.StatusCode, &heartbeat.Status, &heartbeat.Latency, &heartbeat.Message)
+		err := rows.Scan(&heartbeat.ID, &heartbeat.MonitorId, &heartbeat.Timestamp, &heartbeat.StatusCode, &heartbeat.Status, &heartbeat.Latency, &heartbeat.Message, &heartbeat.MaintenanceId)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan rows to get heartbeats: %w", err)
 		}
@@ -35,14 +38,14 @@ func (r *Repository) RetrieveHeartbeats(id, limit int) ([]*models.Heartbeat, err
 	return heartbeats, nil
 }
 
-func (r *Repository) SaveHeartbeat(heartbeat *models.Heartbeat) error {
-	stmt, err := r.db.Prepare("INSERT INTO heartbeats(monitor_id, timestamp, status_code, status, latency, message) VALUES($1, $2, $3, $4, $5, $6)")
+func (r *Repository) SaveHeartbeat(ctx context.Context, heartbeat *models.Heartbeat) error {
+	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO heartbeats(monitor_id, timestamp, status_code, status, latency, message, maintenance_id) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(heartbeat.MonitorId, heartbeat.Timestamp, heartbeat.StatusCode, heartbeat.Status, heartbeat.Latency, heartbeat.Message)
+	err = stmt.QueryRowContext(ctx, heartbeat.MonitorId, heartbeat.Timestamp, heartbeat.StatusCode, heartbeat.Status, heartbeat.Latency, heartbeat.Message, heartbeat.MaintenanceId).Scan(&heartbeat.ID)
 	if err != nil {
 		return fmt.Errorf("failed to save heartbeat: %w", err)
 	}
@@ -51,7 +54,7 @@ func (r *Repository) SaveHeartbeat(heartbeat *models.Heartbeat) error {
 }
 
 func (r *Repository) RetrieveHeartbeatsByTime(id int, startTime time.Time) ([]*models.Heartbeat, error) {
-	stmt, err := r.db.Prepare("SELECT * FROM heartbeats WHERE monitor_id = $1 AND timestamp >= $2 ORDER BY timestamp ASC")
+	stmt, err := r.db.Prepare("SELECT " + heartbeatColumns + " FROM heartbeats WHERE monitor_id = $1 AND timestamp >= $2 ORDER BY timestamp ASC")
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +71,7 @@ func (r *Repository) RetrieveHeartbeatsByTime(id int, startTime time.Time) ([]*m
 
 	for rows.Next() {
 		heartbeat := new(models.Heartbeat)
-		err := rows.Scan(&heartbeat.ID, &heartbeat.MonitorId, &heartbeat.Timestamp, &heartbeat.StatusCode, &heartbeat.Status, &heartbeat.Latency, &heartbeat.Message)
+		err := rows.Scan(&heartbeat.ID, &heartbeat.MonitorId, &heartbeat.Timestamp, &heartbeat.StatusCode, &heartbeat.Status, &heartbeat.Latency, &heartbeat.Message, &heartbeat.MaintenanceId)
 		if err != nil {
 			return nil, fmt.Errorf("faield to scan rows for heartbeats: %w", err)
 		}
@@ -77,3 +80,31 @@ func (r *Repository) RetrieveHeartbeatsByTime(id int, startTime time.Time) ([]*m
 
 	return heartbeats, nil
 }
+
+// RetrieveHeartbeatsBetween returns up to limit heartbeats of the monitor
+// in the time range, oldest first.
+func (r *Repository) RetrieveHeartbeatsBetween(id int, from, to time.Time, limit int) ([]*models.Heartbeat, error) {
+	stmt, err := r.db.Prepare("SELECT " + heartbeatColumns + " FROM heartbeats WHERE monitor_id = $1 AND timestamp >= $2 AND timestamp <= $3 ORDER BY timestamp ASC LIMIT $4")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(id, from.UTC(), to.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get heartbeats: %w", err)
+	}
+	defer rows.Close()
+
+	heartbeats := make([]*models.Heartbeat, 0)
+	for rows.Next() {
+		heartbeat := new(models.Heartbeat)
+		err := rows.Scan(&heartbeat.ID, &heartbeat.MonitorId, &heartbeat.Timestamp, &heartbeat.StatusCode, &heartbeat.Status, &heartbeat.Latency, &heartbeat.Message, &heartbeat.MaintenanceId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rows for heartbeats: %w", err)
+		}
+		heartbeats = append(heartbeats, heartbeat)
+	}
+
+	return heartbeats, nil
+}
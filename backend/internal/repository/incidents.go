@@ -1,34 +1,122 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/chamanbravo/upstat/internal/dto"
 	"github.com/chamanbravo/upstat/internal/models"
 	"github.com/chamanbravo/upstat/svcerr"
 )
 
-func (r *Repository) SaveIncident(incident *dto.SaveIncident) error {
-	stmt, err := r.db.Prepare("INSERT INTO incidents(type, description, is_positive, monitor_id) VALUES($1, $2, $3, $4)")
+const incidentColumns = "id, type, cause, monitor_id, heartbeat_id, started_at, resolved_at, acknowledged_at, acknowledged_by, maintenance_id"
+
+func scanIncident(row scanner) (*models.Incident, error) {
+	incident := new(models.Incident)
+	var heartbeatId, maintenanceId sql.NullInt64
+	var startedAt, resolvedAt, acknowledgedAt sql.NullTime
+
+	err := row.Scan(
+		&incident.ID, &incident.Type, &incident.Cause, &incident.MonitorId, &heartbeatId,
+		&startedAt, &resolvedAt, &acknowledgedAt, &incident.AcknowledgedBy, &maintenanceId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if heartbeatId.Valid {
+		id := int(heartbeatId.Int64)
+		incident.HeartbeatId = &id
+	}
+	if maintenanceId.Valid {
+		id := int(maintenanceId.Int64)
+		incident.MaintenanceId = &id
+	}
+	if startedAt.Valid {
+		incident.StartedAt = &startedAt.Time
+	}
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+	if acknowledgedAt.Valid {
+		incident.AcknowledgedAt = &acknowledgedAt.Time
+	}
+	setIncidentDuration(incident)
+
+	return incident, nil
+}
+
+func setIncidentDuration(incident *models.Incident) {
+	if incident.StartedAt == nil {
+		return
+	}
+
+	end := time.Now()
+	if incident.ResolvedAt != nil {
+		end = *incident.ResolvedAt
+	}
+	incident.Duration = int64(end.Sub(*incident.StartedAt).Seconds())
+}
+
+func (r *Repository) SaveIncident(ctx context.Context, incident *dto.SaveIncident) (*models.Incident, error) {
+	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO incidents(type, cause, monitor_id, heartbeat_id, started_at, maintenance_id) VALUES($1, $2, $3, $4, $5, $6) RETURNING id")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	startedAt := incident.StartedAt.UTC()
+	saved := &models.Incident{
+		Type:          incident.Type,
+		Cause:         incident.Cause,
+		MonitorId:     incident.MonitorId,
+		StartedAt:     &startedAt,
+		MaintenanceId: incident.MaintenanceId,
+	}
+
+	var heartbeatId sql.NullInt64
+	if incident.HeartbeatId != 0 {
+		heartbeatId = sql.NullInt64{Int64: int64(incident.HeartbeatId), Valid: true}
+		saved.HeartbeatId = &incident.HeartbeatId
+	}
+
+	err = stmt.QueryRowContext(ctx, incident.Type, incident.Cause, incident.MonitorId, heartbeatId, startedAt, incident.MaintenanceId).Scan(&saved.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save incident: %w", err)
+	}
+	setIncidentDuration(saved)
+
+	return saved, nil
+}
+
+// ResolveIncident closes the incident, recording when it ended.
+func (r *Repository) ResolveIncident(ctx context.Context, incident *models.Incident, resolvedAt time.Time) error {
+	stmt, err := r.db.PrepareContext(ctx, "UPDATE incidents SET resolved_at = $1 WHERE id = $2")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(incident.Type, incident.Description, incident.IsPositive, incident.MonitorId)
+	resolvedAt = resolvedAt.UTC()
+	_, err = stmt.ExecContext(ctx, resolvedAt, incident.ID)
 	if err != nil {
-		return fmt.Errorf("failed to save incident: %w", err)
+		return fmt.Errorf("failed to resolve incident: %w", err)
 	}
 
+	incident.ResolvedAt = &resolvedAt
+	setIncidentDuration(incident)
+
 	return nil
 }
 
-func (r *Repository) LatestIncidentByMonitorId(id int) (*models.Incident, error) {
-	stmt, err := r.db.Prepare(`
-    SELECT id, type, description, is_positive, monitor_id
+func (r *Repository) OpenIncidentByMonitorId(ctx context.Context, id int) (*models.Incident, error) {
+	stmt, err := r.db.PrepareContext(ctx, `
+    SELECT `+incidentColumns+`
     FROM incidents
-    WHERE monitor_id = $1
+    WHERE monitor_id = $1 AND resolved_at IS NULL
     ORDER BY id DESC
     LIMIT 1;
     `)
@@ -37,16 +125,172 @@ func (r *Repository) LatestIncidentByMonitorId(id int) (*models.Incident, error)
 	}
 	defer stmt.Close()
 
-	row := stmt.QueryRow(id)
+	incident, err := scanIncident(stmt.QueryRowContext(ctx, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, svcerr.ErrNoIncidentsFound
+		}
+		return nil, fmt.Errorf("failed to scan rows of incidents: %w", err)
+	}
+
+	return incident, nil
+}
+
+func (r *Repository) FindIncidentById(id int) (*models.Incident, error) {
+	stmt, err := r.db.Prepare("SELECT " + incidentColumns + " FROM incidents WHERE id = $1")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
 
-	var incident models.Incident
-	err = row.Scan(&incident.ID, &incident.Type, &incident.Description, &incident.IsPositive, &incident.MonitorId)
+	incident, err := scanIncident(stmt.QueryRow(id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, svcerr.ErrNoIncidentsFound
 		}
-		return nil, fmt.Errorf("faield to scan rows of incidents: %w", err)
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+// RetrieveIncidents returns up to limit incidents matching the filter,
+// ordered by id and starting after the cursor.
+func (r *Repository) RetrieveIncidents(filter *dto.IncidentsIn, limit int) ([]*models.Incident, error) {
+	var conditions []string
+	var args []any
+	where := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.MonitorId != 0 {
+		where("monitor_id = $%d", filter.MonitorId)
+	}
+	switch filter.Status {
+	case "open":
+		conditions = append(conditions, "resolved_at IS NULL")
+	case "resolved":
+		conditions = append(conditions, "resolved_at IS NOT NULL")
+	}
+	if filter.Type != "" {
+		where("type = $%d", filter.Type)
+	}
+	if !filter.From.IsZero() {
+		where("(resolved_at IS NULL OR resolved_at >= $%d)", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		where("started_at <= $%d", filter.To.UTC())
+	}
+
+	order, after := "DESC", "<"
+	if filter.Sort == "asc" {
+		order, after = "ASC", ">"
+	}
+	if filter.Cursor != 0 {
+		where("id "+after+" $%d", filter.Cursor)
+	}
+
+	query := "SELECT " + incidentColumns + " FROM incidents"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY id %s LIMIT $%d", order, len(args))
+
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := make([]*models.Incident, 0)
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rows of incidents: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, nil
+}
+
+// AcknowledgeIncident marks the incident as being handled by the user.
+// It fails with svcerr.ErrIncidentClosed if the incident was already
+// acknowledged or resolved.
+func (r *Repository) AcknowledgeIncident(incident *models.Incident, username string, acknowledgedAt time.Time) error {
+	stmt, err := r.db.Prepare("UPDATE incidents SET acknowledged_at = $1, acknowledged_by = $2 WHERE id = $3 AND acknowledged_at IS NULL AND resolved_at IS NULL")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	acknowledgedAt = acknowledgedAt.UTC()
+	result, err := stmt.Exec(acknowledgedAt, username, incident.ID)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge incident: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return svcerr.ErrIncidentClosed
+	}
+
+	incident.AcknowledgedAt = &acknowledgedAt
+	incident.AcknowledgedBy = username
+
+	return nil
+}
+
+func (r *Repository) SaveIncidentNote(note *models.IncidentNote) error {
+	stmt, err := r.db.Prepare("INSERT INTO incident_notes(incident_id, username, text, created_at) VALUES($1, $2, $3, $4) RETURNING id")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	note.CreatedAt = note.CreatedAt.UTC()
+	err = stmt.QueryRow(note.IncidentId, note.Username, note.Text, note.CreatedAt).Scan(&note.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save incident note: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RetrieveIncidentNotes(incidentId int) ([]*models.IncidentNote, error) {
+	stmt, err := r.db.Prepare("SELECT id, incident_id, username, text, created_at FROM incident_notes WHERE incident_id = $1 ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(incidentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := make([]*models.IncidentNote, 0)
+	for rows.Next() {
+		note := new(models.IncidentNote)
+		err = rows.Scan(&note.ID, &note.IncidentId, &note.Username, &note.Text, &note.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rows of incident notes: %w", err)
+		}
+		notes = append(notes, note)
 	}
 
-	return &incident, nil
+	return notes, nil
 }
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/svcerr"
+)
+
+const maintenanceColumns = "w.id, w.title, w.description, w.starts_at, w.ends_at, w.recurrence, w.rule, w.duration, w.timezone, w.created_at"
+
+func scanMaintenanceWindow(row scanner) (*models.MaintenanceWindow, error) {
+	window := new(models.MaintenanceWindow)
+	var endsAt sql.NullTime
+
+	err := row.Scan(
+		&window.ID, &window.Title, &window.Description, &window.StartsAt, &endsAt,
+		&window.Recurrence, &window.Rule, &window.Duration, &window.Timezone, &window.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if endsAt.Valid {
+		window.EndsAt = &endsAt.Time
+	}
+
+	return window, nil
+}
+
+func (r *Repository) CreateMaintenanceWindow(in *dto.MaintenanceWindowIn) (int, error) {
+	if err := r.checkMaintenanceWindowScope(in); err != nil {
+		return 0, err
+	}
+
+	stmt, err := r.db.Prepare("INSERT INTO maintenance_windows(title, description, starts_at, ends_at, recurrence, rule, duration, timezone, created_at) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id")
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var id int
+	err = stmt.QueryRow(
+		in.Title, in.Description, in.StartsAt.UTC(), nullTime(in.EndsAt),
+		in.Recurrence, in.Rule, in.Duration, in.Timezone, time.Now().UTC(),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	if err := r.setMaintenanceWindowScope(id, in); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *Repository) UpdateMaintenanceWindow(id int, in *dto.MaintenanceWindowIn) error {
+	if err := r.checkMaintenanceWindowScope(in); err != nil {
+		return err
+	}
+
+	stmt, err := r.db.Prepare("UPDATE maintenance_windows SET title = $1, description = $2, starts_at = $3, ends_at = $4, recurrence = $5, rule = $6, duration = $7, timezone = $8 WHERE id = $9")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(
+		in.Title, in.Description, in.StartsAt.UTC(), nullTime(in.EndsAt),
+		in.Recurrence, in.Rule, in.Duration, in.Timezone, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return svcerr.ErrMaintenanceWindowNotFound
+	}
+
+	return r.setMaintenanceWindowScope(id, in)
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// checkMaintenanceWindowScope makes sure that the monitors and status
+// pages the window is scoped to exist.
+func (r *Repository) checkMaintenanceWindowScope(in *dto.MaintenanceWindowIn) error {
+	scopes := []struct {
+		table, name string
+		ids         []string
+	}{
+		{"monitors", "monitor", in.Monitors},
+		{"status_pages", "status page", in.StatusPages},
+	}
+
+	for _, scope := range scopes {
+		for _, id := range scope.ids {
+			var exists bool
+			err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM "+scope.table+" WHERE id = $1)", id).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("failed to find %v of maintenance window: %w", scope.name, err)
+			}
+			if !exists {
+				return fmt.Errorf("%w: no %v with id %v", svcerr.ErrMaintenanceScopeNotFound, scope.name, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setMaintenanceWindowScope replaces the monitors and status pages the
+// window applies to.
+func (r *Repository) setMaintenanceWindowScope(id int, in *dto.MaintenanceWindowIn) error {
+	scopes := []struct {
+		table, column string
+		ids           []string
+	}{
+		{"maintenance_windows_monitors", "monitor_id", in.Monitors},
+		{"maintenance_windows_status_pages", "status_pages_id", in.StatusPages},
+	}
+
+	for _, scope := range scopes {
+		_, err := r.db.Exec("DELETE FROM "+scope.table+" WHERE maintenance_id = $1", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete scope of maintenance window: %w", err)
+		}
+
+		stmt, err := r.db.Prepare("INSERT INTO " + scope.table + "(maintenance_id, " + scope.column + ") VALUES($1, $2)")
+		if err != nil {
+			return err
+		}
+
+		for _, v := range scope.ids {
+			if _, err := stmt.Exec(id, v); err != nil {
+				stmt.Close()
+				return fmt.Errorf("failed to add scope of maintenance window: %w", err)
+			}
+		}
+		stmt.Close()
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteMaintenanceWindow(id int) error {
+	stmt, err := r.db.Prepare("DELETE FROM maintenance_windows WHERE id = $1")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return svcerr.ErrMaintenanceWindowNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) FindMaintenanceWindowById(id int) (*models.MaintenanceWindow, error) {
+	stmt, err := r.db.Prepare("SELECT " + maintenanceColumns + " FROM maintenance_windows w WHERE w.id = $1")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	window, err := scanMaintenanceWindow(stmt.QueryRow(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, svcerr.ErrMaintenanceWindowNotFound
+		}
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+
+	if err := r.loadMaintenanceWindowScope(context.Background(), window); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+func (r *Repository) ListMaintenanceWindows() ([]*models.MaintenanceWindow, error) {
+	return r.queryMaintenanceWindows(context.Background(), "SELECT "+maintenanceColumns+" FROM maintenance_windows w ORDER BY w.starts_at DESC")
+}
+
+// MaintenanceWindowsByMonitorId returns the windows that apply to the
+// monitor, directly or through one of its status pages.
+func (r *Repository) MaintenanceWindowsByMonitorId(ctx context.Context, id int) ([]*models.MaintenanceWindow, error) {
+	return r.queryMaintenanceWindows(ctx, `
+	SELECT `+maintenanceColumns+`
+	FROM
+		maintenance_windows w
+	WHERE
+		w.id IN (SELECT maintenance_id FROM maintenance_windows_monitors WHERE monitor_id = $1)
+		OR w.id IN (
+			SELECT ws.maintenance_id
+			FROM maintenance_windows_status_pages ws
+			JOIN status_pages_monitors spm ON ws.status_pages_id = spm.status_pages_id
+			WHERE spm.monitor_id = $1
+		)
+	ORDER BY w.id
+	`, id)
+}
+
+// MaintenanceWindowsBySlug returns the windows shown on the status page,
+// the ones scoped to it or to any of its monitors.
+func (r *Repository) MaintenanceWindowsBySlug(slug string) ([]*models.MaintenanceWindow, error) {
+	return r.queryMaintenanceWindows(context.Background(), `
+	SELECT `+maintenanceColumns+`
+	FROM
+		maintenance_windows w
+	WHERE
+		w.id IN (
+			SELECT ws.maintenance_id
+			FROM maintenance_windows_status_pages ws
+			JOIN status_pages sp ON ws.status_pages_id = sp.id
+			WHERE sp.slug = $1
+		)
+		OR w.id IN (
+			SELECT wm.maintenance_id
+			FROM maintenance_windows_monitors wm
+			JOIN status_pages_monitors spm ON wm.monitor_id = spm.monitor_id
+			JOIN status_pages sp ON spm.status_pages_id = sp.id
+			WHERE sp.slug = $1
+		)
+	ORDER BY w.id
+	`, slug)
+}
+
+func (r *Repository) queryMaintenanceWindows(ctx context.Context, query string, args ...any) ([]*models.MaintenanceWindow, error) {
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := make([]*models.MaintenanceWindow, 0)
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rows of maintenance windows: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	rows.Close()
+
+	for _, window := range windows {
+		if err := r.loadMaintenanceWindowScope(ctx, window); err != nil {
+			return nil, err
+		}
+	}
+
+	return windows, nil
+}
+
+func (r *Repository) loadMaintenanceWindowScope(ctx context.Context, window *models.MaintenanceWindow) error {
+	var err error
+	window.Monitors, err = r.queryIds(ctx, "SELECT monitor_id FROM maintenance_windows_monitors WHERE maintenance_id = $1 ORDER BY monitor_id", window.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get monitors of maintenance window: %w", err)
+	}
+
+	window.StatusPages, err = r.queryIds(ctx, "SELECT status_pages_id FROM maintenance_windows_status_pages WHERE maintenance_id = $1 ORDER BY status_pages_id", window.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get status pages of maintenance window: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) queryIds(ctx context.Context, query string, args ...any) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
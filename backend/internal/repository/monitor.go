@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/chamanbravo/upstat/internal/dto"
@@ -11,31 +14,137 @@ import (
 	"github.com/chamanbravo/upstat/svcerr"
 )
 
+const monitorColumns = "id, name, url, type, method, frequency, status, active, paused_by, paused_at, resume_at, timeout, retries, retry_interval, latency_threshold, degraded_after, headers, body, content_type, auth_method, auth_username, auth_password, auth_token, accepted_status_codes, keyword, keyword_regex, keyword_invert, max_body_size, json_assertions, follow_redirects"
+
+// monitorFields are the user-configurable columns of a monitor, in the same
+// order as the values returned by monitorFieldValues.
+var monitorFields = []string{
+	"name", "url", "type", "method", "frequency", "timeout", "retries", "retry_interval", "latency_threshold", "degraded_after", "headers", "body", "content_type",
+	"auth_method", "auth_username", "auth_password", "auth_token",
+	"accepted_status_codes", "keyword", "keyword_regex", "keyword_invert", "max_body_size", "json_assertions", "follow_redirects",
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMonitor(row scanner) (*models.Monitor, error) {
+	monitor := new(models.Monitor)
+	var headers, acceptedStatusCodes, jsonAssertions string
+	var pausedAt, resumeAt sql.NullTime
+	err := row.Scan(
+		&monitor.ID, &monitor.Name, &monitor.Url, &monitor.Type, &monitor.Method, &monitor.Frequency, &monitor.Status,
+		&monitor.Active, &monitor.PausedBy, &pausedAt, &resumeAt, &monitor.Timeout, &monitor.Retries, &monitor.RetryInterval, &monitor.LatencyThreshold, &monitor.DegradedAfter,
+		&headers, &monitor.Body, &monitor.ContentType, &monitor.AuthMethod, &monitor.AuthUsername, &monitor.AuthPassword, &monitor.AuthToken,
+		&acceptedStatusCodes, &monitor.Keyword, &monitor.KeywordRegex, &monitor.KeywordInvert, &monitor.MaxBodySize, &jsonAssertions, &monitor.FollowRedirects,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if pausedAt.Valid {
+		monitor.PausedAt = &pausedAt.Time
+	}
+	if resumeAt.Valid {
+		monitor.ResumeAt = &resumeAt.Time
+	}
+
+	if err := json.Unmarshal([]byte(headers), &monitor.Headers); err != nil {
+		return nil, fmt.Errorf("failed to decode monitor headers: %w", err)
+	}
+	if err := json.Unmarshal([]byte(acceptedStatusCodes), &monitor.AcceptedStatusCodes); err != nil {
+		return nil, fmt.Errorf("failed to decode monitor accepted status codes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(jsonAssertions), &monitor.JsonAssertions); err != nil {
+		return nil, fmt.Errorf("failed to decode monitor json assertions: %w", err)
+	}
+
+	return monitor, nil
+}
+
+func monitorFieldValues(u *dto.AddMonitorIn) ([]any, error) {
+	headers := u.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headersJson, err := json.Marshal(headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode monitor headers: %w", err)
+	}
+
+	acceptedStatusCodes := u.AcceptedStatusCodes
+	if acceptedStatusCodes == nil {
+		acceptedStatusCodes = []string{}
+	}
+	acceptedStatusCodesJson, err := json.Marshal(acceptedStatusCodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode monitor accepted status codes: %w", err)
+	}
+
+	jsonAssertions := u.JsonAssertions
+	if jsonAssertions == nil {
+		jsonAssertions = []models.JsonAssertion{}
+	}
+	jsonAssertionsJson, err := json.Marshal(jsonAssertions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode monitor json assertions: %w", err)
+	}
+
+	authMethod := u.AuthMethod
+	if authMethod == "" {
+		authMethod = "none"
+	}
+
+	followRedirects := u.FollowRedirects == nil || *u.FollowRedirects
+
+	return []any{
+		u.Name, u.URL, u.Type, u.Method, u.Frequency, u.Timeout, u.Retries, u.RetryInterval, u.LatencyThreshold, u.DegradedAfter, string(headersJson), u.Body, u.ContentType,
+		authMethod, u.AuthUsername, u.AuthPassword, u.AuthToken,
+		string(acceptedStatusCodesJson), u.Keyword, u.KeywordRegex, u.KeywordInvert, u.MaxBodySize, string(jsonAssertionsJson), followRedirects,
+	}, nil
+}
+
+func placeholders(from, count int) string {
+	params := make([]string, count)
+	for i := range params {
+		params[i] = fmt.Sprintf("$%d", from+i)
+	}
+
+	return strings.Join(params, ", ")
+}
+
 func (r *Repository) CreateMonitor(u *dto.AddMonitorIn) (*models.Monitor, error) {
-	stmt, err := r.db.Prepare("INSERT INTO monitors(name, url, type, frequency, method, status) VALUES($1, $2, $3, $4, $5, $6) RETURNING id, frequency, url")
+	values, err := monitorFieldValues(u)
+	if err != nil {
+		return nil, err
+	}
+	values = append(values, "green")
+
+	stmt, err := r.db.Prepare(fmt.Sprintf(
+		"INSERT INTO monitors(%s, status) VALUES(%s) RETURNING %s",
+		strings.Join(monitorFields, ", "), placeholders(1, len(values)), monitorColumns,
+	))
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	monitor := new(models.Monitor)
-	result := stmt.QueryRow(u.Name, u.URL, u.Type, u.Frequency, u.Method, "green").Scan(&monitor.ID, &monitor.Frequency, &monitor.Url)
-	if result != nil {
-		return nil, fmt.Errorf("failed to get inserted monitor: %w", result)
+	monitor, err := scanMonitor(stmt.QueryRow(values...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inserted monitor: %w", err)
 	}
 
 	return monitor, nil
 }
 
-func (r *Repository) FindMonitorById(id int) (*models.Monitor, error) {
-	stmt, err := r.db.Prepare("SELECT * FROM monitors WHERE id = $1")
+func (r *Repository) FindMonitorById(ctx context.Context, id int) (*models.Monitor, error) {
+	stmt, err := r.db.PrepareContext(ctx, "SELECT "+monitorColumns+" FROM monitors WHERE id = $1")
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	monitor := new(models.Monitor)
-	err = stmt.QueryRow(id).Scan(&monitor.ID, &monitor.Name, &monitor.Url, &monitor.Type, &monitor.Method, &monitor.Frequency, &monitor.Status)
+	monitor, err := scanMonitor(stmt.QueryRowContext(ctx, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("%w: failed to find monitor with id: %d", err, id)
@@ -48,28 +157,38 @@ func (r *Repository) FindMonitorById(id int) (*models.Monitor, error) {
 }
 
 func (r *Repository) UpdateMonitorById(id int, monitor *dto.AddMonitorIn) error {
-	stmt, err := r.db.Prepare("UPDATE monitors SET name = $1, url = $2, type = $3, method = $4, frequency = $5  WHERE id = $6")
+	values, err := monitorFieldValues(monitor)
+	if err != nil {
+		return err
+	}
+
+	assignments := make([]string, len(monitorFields))
+	for i, field := range monitorFields {
+		assignments[i] = fmt.Sprintf("%s = $%d", field, i+1)
+	}
+
+	stmt, err := r.db.Prepare(fmt.Sprintf("UPDATE monitors SET %s WHERE id = $%d", strings.Join(assignments, ", "), len(values)+1))
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	result := stmt.QueryRow(monitor.Name, monitor.URL, monitor.Type, monitor.Method, monitor.Frequency, id)
-	if result.Err() != nil {
+	_, err = stmt.Exec(append(values, id)...)
+	if err != nil {
 		return fmt.Errorf("failed to update monitor by id: %w", err)
 	}
 
 	return nil
 }
 
-func (r *Repository) RetrieveMonitors() ([]*models.Monitor, error) {
-	stmt, err := r.db.Prepare("SELECT * FROM monitors")
+func (r *Repository) RetrieveMonitors(ctx context.Context) ([]*models.Monitor, error) {
+	stmt, err := r.db.PrepareContext(ctx, "SELECT "+monitorColumns+" FROM monitors")
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query()
+	rows, err := stmt.QueryContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get monitors: %w", err)
 	}
@@ -78,8 +197,7 @@ func (r *Repository) RetrieveMonitors() ([]*models.Monitor, error) {
 	var monitors []*models.Monitor
 
 	for rows.Next() {
-		monitor := new(models.Monitor)
-		err := rows.Scan(&monitor.ID, &monitor.Name, &monitor.Url, &monitor.Type, &monitor.Method, &monitor.Frequency, &monitor.Status)
+		monitor, err := scanMonitor(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row of monitors: %w", err)
 		}
@@ -89,8 +207,8 @@ func (r *Repository) RetrieveMonitors() ([]*models.Monitor, error) {
 	return monitors, nil
 }
 
-func (r *Repository) UpdateMonitorStatus(id int, status string) error {
-	stmt, err := r.db.Prepare("UPDATE monitors SET status = $1 WHERE id = $2")
+func (r *Repository) UpdateMonitorStatus(ctx context.Context, id int, status string) error {
+	stmt, err := r.db.PrepareContext(ctx, "UPDATE monitors SET status = $1 WHERE id = $2")
 	if err != nil {
 		log.Println("Error when trying to prepare statement")
 		log.Println(err)
@@ -98,7 +216,7 @@ func (r *Repository) UpdateMonitorStatus(id int, status string) error {
 	}
 	defer stmt.Close()
 
-	result, err := stmt.Exec(status, id)
+	result, err := stmt.ExecContext(ctx, status, id)
 	if err != nil {
 		return fmt.Errorf("failed to update monitor status")
 	}
@@ -115,6 +233,54 @@ func (r *Repository) UpdateMonitorStatus(id int, status string) error {
 	return nil
 }
 
+func (r *Repository) PauseMonitor(id int, pausedBy string, resumeAt *time.Time) error {
+	stmt, err := r.db.Prepare("UPDATE monitors SET active = false, paused_by = $1, paused_at = $2, resume_at = $3 WHERE id = $4")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(pausedBy, time.Now().UTC(), resumeAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to pause monitor: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: id was not found: %d", svcerr.ErrNoMonitorsFound, id)
+	}
+
+	return nil
+}
+
+func (r *Repository) ResumeMonitor(ctx context.Context, id int) error {
+	stmt, err := r.db.PrepareContext(ctx, "UPDATE monitors SET active = true, paused_by = '', paused_at = NULL, resume_at = NULL WHERE id = $1")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to resume monitor: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: id was not found: %d", svcerr.ErrNoMonitorsFound, id)
+	}
+
+	return nil
+}
+
 func (r *Repository) RetrieveAverageLatency(id int, timestamp time.Time) (float64, error) {
 	stmt, err := r.db.Prepare("SELECT AVG(latency) as average_latency FROM heartbeats WHERE monitor_id = $1 AND timestamp >= $2")
 	if err != nil {
@@ -131,8 +297,11 @@ func (r *Repository) RetrieveAverageLatency(id int, timestamp time.Time) (float6
 	return averageLatency, nil
 }
 
-func (r *Repository) RetrieveUptime(id int, timestamp time.Time) (float64, error) {
-	stmt, err := r.db.Prepare("SELECT (COUNT(CASE WHEN status = 'green' THEN 1 END) * 100.0) / COUNT(*) as green_percentage FROM heartbeats WHERE monitor_id = $1 AND timestamp >= $2")
+// RetrieveUptime counts degraded heartbeats as up, since the monitor was
+// still reachable. With excludeMaintenance the heartbeats taken during
+// maintenance windows are left out.
+func (r *Repository) RetrieveUptime(id int, timestamp time.Time, excludeMaintenance bool) (float64, error) {
+	stmt, err := r.db.Prepare("SELECT COALESCE((COUNT(CASE WHEN status IN ('green', 'orange') THEN 1 END) * 100.0) / NULLIF(COUNT(*), 0), 100) as green_percentage FROM heartbeats WHERE monitor_id = $1 AND timestamp >= $2" + maintenanceFilter(excludeMaintenance))
 	if err != nil {
 		return 0, err
 	}
@@ -147,6 +316,29 @@ func (r *Repository) RetrieveUptime(id int, timestamp time.Time) (float64, error
 	return averageLatency, nil
 }
 
+func (r *Repository) RetrieveDegradedTime(id int, timestamp time.Time, excludeMaintenance bool) (float64, error) {
+	stmt, err := r.db.Prepare("SELECT COALESCE((COUNT(CASE WHEN status = 'orange' THEN 1 END) * 100.0) / NULLIF(COUNT(*), 0), 0) as orange_percentage FROM heartbeats WHERE monitor_id = $1 AND timestamp >= $2" + maintenanceFilter(excludeMaintenance))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var degradedPercentage float64
+	err = stmt.QueryRow(id, timestamp).Scan(&degradedPercentage)
+	if err != nil {
+		return 0, err
+	}
+
+	return degradedPercentage, nil
+}
+
+func maintenanceFilter(excludeMaintenance bool) string {
+	if excludeMaintenance {
+		return " AND maintenance_id IS NULL"
+	}
+	return ""
+}
+
 func (r *Repository) DeleteMonitorById(id int) error {
 	stmt, err := r.db.Prepare("DELETE FROM monitors WHERE id = $1")
 	if err != nil {
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -11,18 +12,13 @@ import (
 )
 
 func (r *Repository) CreateNotificationChannel(nc *dto.NotificationCreateIn) error {
-	stmt, err := r.db.Prepare("INSERT INTO notifications(name, provider, data) VALUES($1, $2, $3)")
+	stmt, err := r.db.Prepare("INSERT INTO notifications(name, provider, data, remind_interval, remind_max) VALUES($1, $2, $3, $4, $5)")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	dataJson, err := json.Marshal(nc.Data)
-	if err != nil {
-		return err
-	}
-
-	_, err = stmt.Exec(nc.Name, nc.Provider, dataJson)
+	_, err = stmt.Exec(nc.Name, nc.Provider, string(nc.Data), nc.RemindInterval, nc.RemindMax)
 	if err != nil {
 		return fmt.Errorf("failed to create new notification channel: %w", err)
 	}
@@ -75,30 +71,31 @@ func (r *Repository) DeleteNotificationChannel(id int) error {
 }
 
 func (r *Repository) UpdateNotificationById(id int, nc *dto.NotificationCreateIn) error {
-	stmt, err := r.db.Prepare("UPDATE notifications SET name = $1, provider = $2, data = $3 WHERE id = $4")
+	stmt, err := r.db.Prepare("UPDATE notifications SET name = $1, provider = $2, data = $3, remind_interval = $4, remind_max = $5 WHERE id = $6")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	dataJson, err := json.Marshal(nc.Data)
+	result, err := stmt.Exec(nc.Name, nc.Provider, string(nc.Data), nc.RemindInterval, nc.RemindMax, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to update notification channel: %w", err)
 	}
 
-	result := stmt.QueryRow(nc.Name, nc.Provider, dataJson, id)
-	if result != nil {
-		if err == sql.ErrNoRows {
-			return svcerr.ErrNotificationsNotFound
-		}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
 		return err
 	}
 
+	if rowsAffected == 0 {
+		return svcerr.ErrNotificationsNotFound
+	}
+
 	return nil
 }
 
 func (r *Repository) FindNotificationById(id int) (*models.Notification, error) {
-	stmt, err := r.db.Prepare("SELECT id, name, provider, data::text FROM notifications WHERE id = $1")
+	stmt, err := r.db.Prepare("SELECT id, name, provider, CAST(data AS TEXT), remind_interval, remind_max FROM notifications WHERE id = $1")
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +104,7 @@ func (r *Repository) FindNotificationById(id int) (*models.Notification, error)
 	notification := new(models.Notification)
 	var dataStr string
 
-	err = stmt.QueryRow(id).Scan(&notification.ID, &notification.Name, &notification.Provider, &dataStr)
+	err = stmt.QueryRow(id).Scan(&notification.ID, &notification.Name, &notification.Provider, &dataStr, &notification.RemindInterval, &notification.RemindMax)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, svcerr.ErrNotificationsNotFound
@@ -116,9 +113,7 @@ func (r *Repository) FindNotificationById(id int) (*models.Notification, error)
 		return nil, fmt.Errorf("failed to get notification channel: %w", err)
 	}
 
-	if err := json.Unmarshal([]byte(dataStr), &notification.Data); err != nil {
-		return nil, err
-	}
+	notification.Data = json.RawMessage(dataStr)
 
 	return notification, nil
 }
@@ -140,13 +135,13 @@ func (r *Repository) NotificationMonitor(monitorId int, notificationChannels []s
 	return nil
 }
 
-func (r *Repository) FindNotificationChannelsByMonitorId(id int) ([]models.Notification, error) {
-	stmt, err := r.db.Prepare(`
+func (r *Repository) FindNotificationChannelsByMonitorId(ctx context.Context, id int) ([]models.Notification, error) {
+	stmt, err := r.db.PrepareContext(ctx, `
 	SELECT
         n.id,
 		n.name AS notification_name,
 		n.provider,
-		n.data::text
+		CAST(n.data AS TEXT)
 	FROM
 		notifications_monitors nm
 	JOIN
@@ -161,7 +156,7 @@ func (r *Repository) FindNotificationChannelsByMonitorId(id int) ([]models.Notif
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(id)
+	rows, err := stmt.QueryContext(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get notifications: %w", err)
 	}
@@ -176,9 +171,7 @@ func (r *Repository) FindNotificationChannelsByMonitorId(id int) ([]models.Notif
 			return nil, fmt.Errorf("failed to scan row of notifications: %w", err)
 		}
 
-		if err := json.Unmarshal([]byte(dataStr), &notification.Data); err != nil {
-			return nil, err
-		}
+		notification.Data = json.RawMessage(dataStr)
 
 		notifications = append(notifications, notification)
 	}
@@ -1,6 +1,9 @@
 package repository
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+)
 
 type Repository struct {
 	db *sql.DB
@@ -11,3 +14,28 @@ func New(db *sql.DB) *Repository {
 		db,
 	}
 }
+
+// preparer prepares statements on the database or in a transaction.
+type preparer interface {
+	Prepare(query string) (*sql.Stmt, error)
+}
+
+// inTx runs fn in a transaction, which is committed if fn succeeds and
+// rolled back otherwise.
+func (r *Repository) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/svcerr"
+)
+
+const statusPageIncidentColumns = "i.id, i.title, i.severity, i.status, i.created_at, i.resolved_at"
+
+func scanStatusPageIncident(row scanner) (*models.StatusPageIncident, error) {
+	incident := new(models.StatusPageIncident)
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(&incident.ID, &incident.Title, &incident.Severity, &incident.Status, &incident.CreatedAt, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+
+	return incident, nil
+}
+
+// CreateStatusPageIncident saves the incident along with its first update
+// and returns its id.
+func (r *Repository) CreateStatusPageIncident(in *dto.CreateStatusPageIncidentIn, username string) (int, error) {
+	if err := r.checkStatusPageIncidentPages(in.StatusPages); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := r.inTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare("INSERT INTO status_page_incidents(title, severity, status, created_at) VALUES($1, $2, $3, $4) RETURNING id")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		createdAt := time.Now().UTC()
+		err = stmt.QueryRow(in.Title, in.Severity, in.Status, createdAt).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to create status page incident: %w", err)
+		}
+
+		if err := setStatusPageIncidentPages(tx, id, in.StatusPages); err != nil {
+			return err
+		}
+
+		return addStatusPageIncidentUpdate(tx, &models.StatusPageIncidentUpdate{
+			IncidentId: id, Status: in.Status, Message: in.Message, Username: username, CreatedAt: createdAt,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *Repository) UpdateStatusPageIncident(id int, in *dto.StatusPageIncidentIn) error {
+	if err := r.checkStatusPageIncidentPages(in.StatusPages); err != nil {
+		return err
+	}
+
+	return r.inTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare("UPDATE status_page_incidents SET title = $1, severity = $2 WHERE id = $3")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		result, err := stmt.Exec(in.Title, in.Severity, id)
+		if err != nil {
+			return fmt.Errorf("failed to update status page incident: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return svcerr.ErrStatusPageIncidentNotFound
+		}
+
+		return setStatusPageIncidentPages(tx, id, in.StatusPages)
+	})
+}
+
+// checkStatusPageIncidentPages makes sure that the status pages an
+// incident is posted to exist.
+func (r *Repository) checkStatusPageIncidentPages(statusPages []string) error {
+	for _, statusPage := range statusPages {
+		var exists bool
+		err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM status_pages WHERE id = $1)", statusPage).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to find status page of incident: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: no status page with id %v", svcerr.ErrStatusPageIncidentPageNotFound, statusPage)
+		}
+	}
+
+	return nil
+}
+
+func setStatusPageIncidentPages(db preparer, id int, statusPages []string) error {
+	stmt, err := db.Prepare("DELETE FROM status_page_incidents_status_pages WHERE incident_id = $1")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(id); err != nil {
+		return fmt.Errorf("failed to delete status pages of incident: %w", err)
+	}
+
+	insert, err := db.Prepare("INSERT INTO status_page_incidents_status_pages(incident_id, status_pages_id) VALUES($1, $2)")
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	for _, statusPage := range statusPages {
+		if _, err := insert.Exec(id, statusPage); err != nil {
+			return fmt.Errorf("failed to add status page to incident: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteStatusPageIncident(id int) error {
+	stmt, err := r.db.Prepare("DELETE FROM status_page_incidents WHERE id = $1")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(id)
+	if err != nil {
+		return fmt.Errorf("failed to delete status page incident: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return svcerr.ErrStatusPageIncidentNotFound
+	}
+
+	return nil
+}
+
+// AddStatusPageIncidentUpdate adds the update to the timeline of the
+// incident, which takes on its status and is resolved or reopened by it.
+func (r *Repository) AddStatusPageIncidentUpdate(update *models.StatusPageIncidentUpdate) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		return addStatusPageIncidentUpdate(tx, update)
+	})
+}
+
+func addStatusPageIncidentUpdate(db preparer, update *models.StatusPageIncidentUpdate) error {
+	stmt, err := db.Prepare("UPDATE status_page_incidents SET status = $1, resolved_at = $2 WHERE id = $3")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	update.CreatedAt = update.CreatedAt.UTC()
+	var resolvedAt sql.NullTime
+	if update.Status == "resolved" {
+		resolvedAt = sql.NullTime{Time: update.CreatedAt, Valid: true}
+	}
+
+	result, err := stmt.Exec(update.Status, resolvedAt, update.IncidentId)
+	if err != nil {
+		return fmt.Errorf("failed to update status page incident: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return svcerr.ErrStatusPageIncidentNotFound
+	}
+
+	insert, err := db.Prepare("INSERT INTO status_page_incident_updates(incident_id, status, message, username, created_at) VALUES($1, $2, $3, $4, $5) RETURNING id")
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	err = insert.QueryRow(update.IncidentId, update.Status, update.Message, update.Username, update.CreatedAt).Scan(&update.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save status page incident update: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) FindStatusPageIncidentById(id int) (*models.StatusPageIncident, error) {
+	stmt, err := r.db.Prepare("SELECT " + statusPageIncidentColumns + " FROM status_page_incidents i WHERE i.id = $1")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	incident, err := scanStatusPageIncident(stmt.QueryRow(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, svcerr.ErrStatusPageIncidentNotFound
+		}
+		return nil, fmt.Errorf("failed to get status page incident: %w", err)
+	}
+
+	if err := r.loadStatusPageIncident(incident); err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+func (r *Repository) ListStatusPageIncidents() ([]*models.StatusPageIncident, error) {
+	return r.queryStatusPageIncidents("SELECT " + statusPageIncidentColumns + " FROM status_page_incidents i ORDER BY i.id DESC")
+}
+
+// StatusPageIncidentsBySlug returns the incidents shown on the status page
+// that are still open or were resolved after since, newest first.
+func (r *Repository) StatusPageIncidentsBySlug(slug string, since time.Time) ([]*models.StatusPageIncident, error) {
+	return r.queryStatusPageIncidents(`
+	SELECT `+statusPageIncidentColumns+`
+	FROM
+		status_page_incidents i
+	JOIN
+		status_page_incidents_status_pages isp ON isp.incident_id = i.id
+	JOIN
+		status_pages sp ON isp.status_pages_id = sp.id
+	WHERE
+		sp.slug = $1 AND (i.resolved_at IS NULL OR i.resolved_at >= $2)
+	ORDER BY i.id DESC
+	`, slug, since.UTC())
+}
+
+func (r *Repository) queryStatusPageIncidents(query string, args ...any) ([]*models.StatusPageIncident, error) {
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status page incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := make([]*models.StatusPageIncident, 0)
+	for rows.Next() {
+		incident, err := scanStatusPageIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rows of status page incidents: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	rows.Close()
+
+	for _, incident := range incidents {
+		if err := r.loadStatusPageIncident(incident); err != nil {
+			return nil, err
+		}
+	}
+
+	return incidents, nil
+}
+
+// loadStatusPageIncident fills in the status pages and the timeline of
+// updates of the incident, newest update first.
+func (r *Repository) loadStatusPageIncident(incident *models.StatusPageIncident) error {
+	pages, err := r.db.Query("SELECT status_pages_id FROM status_page_incidents_status_pages WHERE incident_id = $1 ORDER BY status_pages_id", incident.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get status pages of incident: %w", err)
+	}
+	defer pages.Close()
+
+	incident.StatusPages = make([]int, 0)
+	for pages.Next() {
+		var id int
+		if err := pages.Scan(&id); err != nil {
+			return fmt.Errorf("failed to scan rows of status pages of incident: %w", err)
+		}
+		incident.StatusPages = append(incident.StatusPages, id)
+	}
+
+	updates, err := r.db.Query("SELECT id, incident_id, status, message, username, created_at FROM status_page_incident_updates WHERE incident_id = $1 ORDER BY id DESC", incident.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get status page incident updates: %w", err)
+	}
+	defer updates.Close()
+
+	incident.Updates = make([]*models.StatusPageIncidentUpdate, 0)
+	for updates.Next() {
+		update := new(models.StatusPageIncidentUpdate)
+		err := updates.Scan(&update.ID, &update.IncidentId, &update.Status, &update.Message, &update.Username, &update.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to scan rows of status page incident updates: %w", err)
+		}
+		incident.Updates = append(incident.Updates, update)
+	}
+
+	return nil
+}
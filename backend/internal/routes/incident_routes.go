@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/chamanbravo/upstat/internal/controllers/rest"
+	"github.com/chamanbravo/upstat/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Group Incidents
+func IncidentRoutes(app *fiber.App, h *controllers.Handler) {
+	route := app.Group("/api/incidents", middleware.Protected)
+
+	route.Get("", h.IncidentsList)
+	route.Get("/:id", h.IncidentInfo)
+	route.Post("/:id/acknowledge", h.AcknowledgeIncident)
+	route.Post("/:id/notes", h.AddIncidentNote)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/chamanbravo/upstat/internal/controllers/rest"
+	"github.com/chamanbravo/upstat/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Group Maintenance
+func MaintenanceRoutes(app *fiber.App, h *controllers.Handler) {
+	route := app.Group("/api/maintenance", middleware.Protected)
+
+	route.Post("", h.CreateMaintenanceWindow)
+	route.Get("", h.ListMaintenanceWindows)
+	route.Get("/:id", h.MaintenanceWindowInfo)
+	route.Patch("/:id", h.UpdateMaintenanceWindow)
+	route.Delete("/:id", h.DeleteMaintenanceWindow)
+}
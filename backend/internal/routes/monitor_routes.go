@@ -18,8 +18,10 @@ func MonitorRoutes(app *fiber.App, h *controllers.Handler) {
 	route.Delete("/:id", h.DeleteMonitor)
 	route.Patch(":id/pause", h.PauseMonitor)
 	route.Patch(":id/resume", h.ResumeMonitor)
+	route.Post("/:id/check", h.CheckMonitor)
 	route.Get("/:id/summary", h.MonitorSummary)
 	route.Get("/:id/heartbeat", h.RetrieveHeartbeat)
+	route.Get("/:id/incidents", h.MonitorIncidents)
 	route.Get("/:id/cert-exp-countdown", h.CertificateExpiryCountDown)
 	route.Get("/:id/notifications", h.NotificationChannelListOfMonitor)
 	route.Get("/:id/status-pages", h.StatusPagesListOfMonitor)
@@ -13,7 +13,10 @@ func NotificationRoutes(app *fiber.App, h *controllers.Handler) {
 
 	route.Post("", h.CreateNotification)
 	route.Get("", h.ListNotificationsChannel)
+	route.Post("/test", h.TestNotification)
 	route.Delete("/:id", h.DeleteNotificationChannel)
 	route.Patch("/:id", h.UpdateNotificationChannel)
 	route.Get("/:id", h.NotificationChannelInfo)
+	route.Get("/:id/deliveries", h.NotificationDeliveries)
+	route.Post("/:id/test", h.TestNotificationChannel)
 }
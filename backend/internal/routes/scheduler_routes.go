@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/chamanbravo/upstat/internal/controllers/rest"
+	"github.com/chamanbravo/upstat/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Group Scheduler
+func SchedulerRoutes(app *fiber.App, h *controllers.Handler) {
+	route := app.Group("/api/scheduler", middleware.Protected)
+
+	route.Get("/stats", h.SchedulerStats)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/chamanbravo/upstat/internal/controllers/rest"
+	"github.com/chamanbravo/upstat/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Group Status Page Incidents
+func StatusPageIncidentRoutes(app *fiber.App, h *controllers.Handler) {
+	route := app.Group("/api/status-page-incidents", middleware.Protected)
+
+	route.Post("", h.CreateStatusPageIncident)
+	route.Get("", h.ListStatusPageIncidents)
+	route.Get("/:id", h.StatusPageIncidentInfo)
+	route.Patch("/:id", h.UpdateStatusPageIncident)
+	route.Delete("/:id", h.DeleteStatusPageIncident)
+	route.Post("/:id/updates", h.AddStatusPageIncidentUpdate)
+}
@@ -0,0 +1,338 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Events about an open incident that are broadcast besides the changes of
+// a monitor's status.
+const (
+	EventAcknowledged = "acknowledged"
+	EventNote         = "note"
+)
+
+// Alert is a change of a monitor's status that is delivered to its
+// notification channels.
+type Alert struct {
+	Type      string            `json:"type"`
+	Monitor   *models.Monitor   `json:"monitor"`
+	Heartbeat *models.Heartbeat `json:"heartbeat"`
+	Incident  *models.Incident  `json:"incident"`
+	// Resolved is the previously open incident that this status change
+	// ends, if any.
+	Resolved *models.Incident `json:"resolved"`
+	Time     time.Time        `json:"time"`
+	// Duration is how long the monitor was down or degraded, set when
+	// it recovers and on reminders.
+	Duration time.Duration `json:"duration"`
+	// Reminder numbers the reminders of an incident that is still open,
+	// it is 0 for the alert sent when the status changed.
+	Reminder int `json:"reminder"`
+	// Event is set for alerts about the Incident that are not a status
+	// change, User is who caused the event.
+	Event string               `json:"event"`
+	User  string               `json:"user"`
+	Note  *models.IncidentNote `json:"note"`
+	// DeliveryId is the outbox entry the alert is delivered from. It stays
+	// the same across retries, so providers can deduplicate them; it is 0
+	// for test alerts.
+	DeliveryId int `json:"-"`
+}
+
+// Title returns a one line summary of the alert.
+func (a *Alert) Title() string {
+	switch a.Event {
+	case EventAcknowledged:
+		return fmt.Sprintf("%v acknowledged the incident of your monitor %v", a.User, a.Monitor.Name)
+	case EventNote:
+		return fmt.Sprintf("%v added a note to the incident of your monitor %v", a.User, a.Monitor.Name)
+	}
+
+	still := ""
+	if a.Reminder > 0 {
+		still = "still "
+	}
+
+	switch a.Type {
+	case "UP":
+		return fmt.Sprintf("Your monitor %v is UP", a.Monitor.Name)
+	case "DEGRADED":
+		return fmt.Sprintf("Your monitor %v is %sdegraded", a.Monitor.Name, still)
+	default:
+		return fmt.Sprintf("Your monitor %v is %sdown", a.Monitor.Name, still)
+	}
+}
+
+// Text returns the details of the alert as plain text.
+func (a *Alert) Text() string {
+	text := fmt.Sprintf("Monitor: %v | URL: %v", a.Monitor.Name, a.Monitor.Url)
+	if a.Event != "" {
+		text += fmt.Sprintf("\nIncident: %v", a.Incident.Type)
+		if a.Incident.StartedAt != nil {
+			text += fmt.Sprintf(" since %v", a.Incident.StartedAt.UTC().Format("2006-01-02 15:04:05 UTC"))
+		}
+		if a.Note != nil {
+			text += fmt.Sprintf("\nNote: %v", a.Note.Text)
+		}
+		return text
+	}
+
+	switch a.Type {
+	case "UP":
+		text += fmt.Sprintf("\nStatus Code: %v | Latency: %vms", a.Heartbeat.StatusCode, a.Heartbeat.Latency)
+		if a.Duration > 0 {
+			text += fmt.Sprintf("\nOutage duration: %v", FormatDuration(a.Duration))
+		}
+	case "DEGRADED":
+		text += fmt.Sprintf("\nLatency: %vms | Threshold: %vms", a.Heartbeat.Latency, a.Monitor.LatencyThreshold)
+		if a.Duration > 0 {
+			text += fmt.Sprintf("\nDegraded for: %v", FormatDuration(a.Duration))
+		}
+	default:
+		if a.Heartbeat.Message != "" {
+			text += fmt.Sprintf("\nReason: %v", a.Heartbeat.Message)
+		}
+		if a.Duration > 0 {
+			text += fmt.Sprintf("\nDown for: %v", FormatDuration(a.Duration))
+		}
+	}
+
+	return text
+}
+
+// EventDetails returns the details of an event alert as name and value
+// pairs, in place of the status details of the heartbeat.
+func (a *Alert) EventDetails() [][2]string {
+	incident := a.Incident.Type
+	if a.Incident.StartedAt != nil {
+		incident += " since " + a.Incident.StartedAt.UTC().Format("2006-01-02 15:04:05 UTC")
+	}
+
+	details := [][2]string{{"Monitor", a.Monitor.Name}, {"URL", a.Monitor.Url}, {"Incident", incident}}
+	switch a.Event {
+	case EventAcknowledged:
+		details = append(details, [2]string{"Acknowledged by", a.User})
+	case EventNote:
+		details = append(details, [2]string{"Note by", a.User})
+		if a.Note != nil {
+			details = append(details, [2]string{"Note", a.Note.Text})
+		}
+	}
+
+	return details
+}
+
+// TestAlert returns a sample DOWN alert that is sent to check that a
+// notification channel is configured correctly.
+func TestAlert() *Alert {
+	now := time.Now()
+
+	return &Alert{
+		Type: "DOWN",
+		Monitor: &models.Monitor{
+			Name:   "Upstat test monitor",
+			Url:    "https://example.com",
+			Type:   "http",
+			Status: "red",
+		},
+		Heartbeat: &models.Heartbeat{
+			Timestamp:  now,
+			StatusCode: "500",
+			Status:     "red",
+			Message:    "This is a test notification from Upstat",
+		},
+		Incident: &models.Incident{
+			Type:      "DOWN",
+			Cause:     "This is a test notification from Upstat",
+			StartedAt: &now,
+		},
+		Time: now,
+	}
+}
+
+// TestResolution returns the UP alert that resolves the incident of the
+// test alert.
+func TestResolution(test *Alert) *Alert {
+	return &Alert{
+		Type:      "UP",
+		Monitor:   test.Monitor,
+		Heartbeat: test.Heartbeat,
+		Resolved:  test.Incident,
+		Time:      time.Now(),
+	}
+}
+
+// FormatDuration rounds the duration to whole seconds for display.
+func FormatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// Notifier delivers alerts to a notification provider. Notifiers are
+// decoded from the data of a notification channel, so their exported
+// fields double as the provider config and carry its validation rules.
+type Notifier interface {
+	Send(ctx context.Context, alert *Alert) (*Response, error)
+}
+
+// Resolver is implemented by notifiers whose provider keeps an alert open
+// until it is resolved, like an on-call incident.
+type Resolver interface {
+	Resolve(ctx context.Context, incident *models.Incident) (*Response, error)
+}
+
+// Response is what the provider answered to a delivered alert.
+type Response struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+var providers = map[string]func() Notifier{}
+
+// Register makes a notification provider available under the given name.
+func Register(name string, provider func() Notifier) {
+	providers[name] = provider
+}
+
+// Resolves reports whether the notifiers of the provider keep an alert
+// open until its incident is resolved.
+func Resolves(provider string) bool {
+	newNotifier, ok := providers[provider]
+	if !ok {
+		return false
+	}
+
+	_, ok = newNotifier().(Resolver)
+	return ok
+}
+
+// Providers returns the names of the registered notification providers.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// New decodes the data of a notification channel into the notifier of
+// its provider.
+func New(provider string, data json.RawMessage) (Notifier, error) {
+	newNotifier, ok := providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification provider %q", provider)
+	}
+
+	notifier := newNotifier()
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, notifier); err != nil {
+			return nil, fmt.Errorf("invalid %s notification data: %w", provider, err)
+		}
+	}
+
+	return notifier, nil
+}
+
+// StatusError is returned when a provider responds with a non-2xx status.
+// RetryAfter is set when the provider asked to retry after a delay.
+type StatusError struct {
+	Response
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %d: %s", e.StatusCode, e.Body)
+}
+
+// Temporary reports whether delivering the alert again may succeed.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+var client = &http.Client{Timeout: defaultTimeout}
+
+// DedupKey identifies the incident in providers that deduplicate alerts.
+func DedupKey(incident *models.Incident) string {
+	return fmt.Sprintf("upstat-monitor-%d-incident-%d", incident.MonitorId, incident.ID)
+}
+
+// postJSON posts the payload as JSON and reports non-2xx responses as a
+// StatusError.
+func postJSON(ctx context.Context, url string, payload any) (*Response, error) {
+	request, err := newJSONRequest(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return do(request)
+}
+
+func newJSONRequest(ctx context.Context, method, url string, payload any) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	return request, nil
+}
+
+func do(request *http.Request) (*Response, error) {
+	response, err := client.Do(request)
+	if err != nil {
+		// The URL of many providers carries a token or webhook secret,
+		// and the error is stored and shown with the delivery.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, fmt.Errorf("%s request to %s failed: %w", urlErr.Op, request.URL.Host, urlErr.Err)
+		}
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	result := &Response{StatusCode: response.StatusCode, Body: string(body)}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return result, &StatusError{Response: *result, RetryAfter: retryAfter(response.Header.Get("Retry-After"))}
+	}
+
+	return result, nil
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date.
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,95 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// capturedRequest is a request received by a provider stand-in.
+type capturedRequest struct {
+	method string
+	// uri is the escaped path and query of the request.
+	uri    string
+	header http.Header
+	body   []byte
+}
+
+// decode unmarshals the JSON body of the request into v.
+func (r capturedRequest) decode(t *testing.T, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(r.body, v); err != nil {
+		t.Fatalf("invalid payload %s: %v", r.body, err)
+	}
+}
+
+// standIn starts a provider stand-in that answers every request with the
+// given status and passes the requests it receives on.
+func standIn(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+
+	requests := make(chan capturedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{method: r.Method, uri: r.URL.RequestURI(), header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+// rateLimited starts a provider stand-in that answers every request with
+// 429 Too Many Requests and the given Retry-After header.
+func rateLimited(t *testing.T, retryAfter string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", retryAfter)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// assertRetryAfter checks that err is a temporary StatusError asking to
+// retry after the given delay.
+func assertRetryAfter(t *testing.T, err error, want time.Duration) {
+	t.Helper()
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("got %v, want a StatusError", err)
+	}
+	if !statusErr.Temporary() || statusErr.RetryAfter != want {
+		t.Errorf("got temporary %v and retry after %v, want %v", statusErr.Temporary(), statusErr.RetryAfter, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"", 0, 0},
+		{"120", 2 * time.Minute, 2 * time.Minute},
+		{"0", 0, 0},
+		{"-5", 0, 0},
+		{"soon", 0, 0},
+		{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 59 * time.Minute, time.Hour},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("%q: got %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
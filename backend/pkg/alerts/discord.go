@@ -1,10 +1,8 @@
 package alerts
 
 import (
+	"context"
 	"fmt"
-	"time"
-
-	"github.com/chamanbravo/upstat/internal/models"
 )
 
 type Embeds struct {
@@ -20,35 +18,37 @@ type DiscordWebhookMessage struct {
 	Embeds    []Embeds `json:"embeds"`
 }
 
-func DiscordAlertMessage(heartbeat *models.Heartbeat, monitor *models.Monitor) DiscordWebhookMessage {
-	var discordMessage DiscordWebhookMessage
-	if heartbeat.Status == "green" {
-		discordMessage = DiscordWebhookMessage{
-			Username:  "Upstat",
-			AvatarURL: "https://raw.githubusercontent.com/chamanbravo/upstat/main/docs/assets/upstat.png", // Upstat avatar
-			Embeds: []Embeds{
-				{
-					Title:       fmt.Sprintf("✅ Your monitor %v is UP ✅", monitor.Name),
-					Time:        time.Now().Format("2006-01-02 15:04:05"),
-					Description: fmt.Sprintf("Monitor: **%v** | URL: **%v**\nStatus Code: **%v** | Latency: **%vms**", monitor.Name, monitor.Url, heartbeat.StatusCode, heartbeat.Latency),
-					Color:       65280, // green
-				},
-			},
-		}
-	} else if heartbeat.Status == "red" {
-		discordMessage = DiscordWebhookMessage{
-			Username:  "Upstat",
-			AvatarURL: "https://raw.githubusercontent.com/chamanbravo/upstat/main/docs/assets/upstat.png", // Upstat avatar
-			Embeds: []Embeds{
-				{
-					Title:       fmt.Sprintf("❌ Your monitor %v is down ❌", monitor.Name),
-					Time:        time.Now().Format("2006-01-02 15:04:05"),
-					Description: fmt.Sprintf("Monitor: %v | URL: %v ", monitor.Name, monitor.Url),
-					Color:       16711680, // red
-				},
-			},
-		}
+type Discord struct {
+	WebhookUrl string `json:"webhookUrl" validate:"required,url"`
+}
+
+func init() {
+	Register("Discord", func() Notifier { return new(Discord) })
+}
+
+func (d *Discord) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	return postJSON(ctx, d.WebhookUrl, DiscordAlertMessage(alert))
+}
+
+func DiscordAlertMessage(alert *Alert) DiscordWebhookMessage {
+	emoji, color := "❌", 16711680 // red
+	switch alert.Type {
+	case "UP":
+		emoji, color = "✅", 65280 // green
+	case "DEGRADED":
+		emoji, color = "⚠️", 16753920 // orange
 	}
 
-	return discordMessage
+	return DiscordWebhookMessage{
+		Username:  "Upstat",
+		AvatarURL: "https://raw.githubusercontent.com/chamanbravo/upstat/main/docs/assets/upstat.png", // Upstat avatar
+		Embeds: []Embeds{
+			{
+				Title:       fmt.Sprintf("%s %s %s", emoji, alert.Title(), emoji),
+				Time:        alert.Time.Format("2006-01-02 15:04:05"),
+				Description: alert.Text(),
+				Color:       color,
+			},
+		},
+	}
 }
@@ -0,0 +1,202 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Email struct {
+	Host     string   `json:"host" validate:"required"`
+	Port     int      `json:"port" validate:"required,min=1,max=65535"`
+	TLSMode  string   `json:"tlsMode" validate:"omitempty,oneof=none starttls tls"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from" validate:"required,email"`
+	To       []string `json:"to" validate:"required,min=1,dive,email"`
+}
+
+func init() {
+	Register("Email", func() Notifier { return new(Email) })
+}
+
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2 style="color: {{.Color}};">{{.Title}}</h2>
+<table cellpadding="4">
+{{range .Rows}}<tr><td><strong>{{index . 0}}</strong></td><td>{{index . 1}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func (e *Email) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	message, err := EmailAlertMessage(alert, e.From, e.To)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := e.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	stop := context.AfterFunc(ctx, func() { client.Close() })
+	defer stop()
+
+	if e.TLSMode == "starttls" {
+		if err := client.StartTLS(&tls.Config{ServerName: e.Host}); err != nil {
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if e.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.Username, e.Password, e.Host)); err != nil {
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(e.From); err != nil {
+		return nil, fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, to := range e.To {
+		if err := client.Rcpt(to); err != nil {
+			return nil, fmt.Errorf("failed to add recipient %v: %w", to, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	// The server accepted the message once the data is closed, failing to
+	// quit must not send it again.
+	if err := client.Quit(); err != nil {
+		log.Printf("Error when trying to quit SMTP session: %v", err.Error())
+	}
+
+	return nil, nil
+}
+
+func (e *Email) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+
+	var conn net.Conn
+	var err error
+	if e.TLSMode == "tls" {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: e.Host}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, e.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// EmailAlertMessage builds a multipart message with a plain text and an
+// HTML version of the alert.
+func EmailAlertMessage(alert *Alert, from string, to []string) ([]byte, error) {
+	color, rows := "#dc2626", [][2]string{
+		{"Monitor", alert.Monitor.Name},
+		{"URL", alert.Monitor.Url},
+		{"Status Code", alert.Heartbeat.StatusCode},
+		{"Latency", fmt.Sprintf("%vms", alert.Heartbeat.Latency)},
+	}
+	switch alert.Type {
+	case "UP":
+		color = "#16a34a"
+	case "DEGRADED":
+		color = "#ea580c"
+	}
+	if alert.Event != "" {
+		color, rows = "#2563eb", alert.EventDetails()
+	} else {
+		if alert.Type != "UP" && alert.Heartbeat.Message != "" {
+			rows = append(rows, [2]string{"Reason", alert.Heartbeat.Message})
+		}
+		if alert.Duration > 0 {
+			rows = append(rows, [2]string{"Outage duration", FormatDuration(alert.Duration)})
+		}
+	}
+	rows = append(rows, [2]string{"Time", alert.Time.UTC().Format("2006-01-02 15:04:05 UTC")})
+
+	var html bytes.Buffer
+	err := emailTemplate.Execute(&html, map[string]any{"Title": alert.Title(), "Color": color, "Rows": rows})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	var message bytes.Buffer
+	body := multipart.NewWriter(&message)
+
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "[Upstat] "+alert.Title()))
+	fmt.Fprintf(&message, "Date: %s\r\n", alert.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", body.Boundary())
+
+	parts := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", alert.Title() + "\n\n" + alert.Text() + "\n"},
+		{"text/html; charset=utf-8", html.String()},
+	}
+	for _, part := range parts {
+		writer, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	return message.Bytes(), nil
+}
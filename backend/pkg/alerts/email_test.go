@@ -0,0 +1,165 @@
+package alerts
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// smtpSession is what a fakeSMTP server received in a session.
+type smtpSession struct {
+	commands []string
+	data     string
+}
+
+// fakeSMTP accepts a single SMTP session without STARTTLS or auth and
+// returns an Email that sends to it. Commands are answered from replies
+// when given, and with a success reply otherwise.
+func fakeSMTP(t *testing.T, replies map[string]string) (*Email, <-chan smtpSession) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var session smtpSession
+		defer func() { sessions <- session }()
+
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.Fields(line + " ")[0])
+			session.commands = append(session.commands, command)
+
+			if reply, ok := replies[command]; ok {
+				text.PrintfLine("%s", reply)
+				if command == "QUIT" {
+					return
+				}
+				continue
+			}
+
+			switch command {
+			case "EHLO":
+				text.PrintfLine("250-localhost")
+				text.PrintfLine("250 8BITMIME")
+			case "DATA":
+				text.PrintfLine("354 Go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				session.data = string(data)
+				text.PrintfLine("250 Queued")
+			case "QUIT":
+				text.PrintfLine("221 Bye")
+				return
+			default:
+				text.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+	email := &Email{Host: address.IP.String(), Port: address.Port, TLSMode: "none", From: "upstat@example.com"}
+
+	return email, sessions
+}
+
+func TestEmailSend(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies map[string]string
+	}{
+		{"quit", nil},
+		{"failed quit", map[string]string{"QUIT": "554 Transaction failed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email, sessions := fakeSMTP(t, tt.replies)
+			email.To = []string{"ops@example.com", "dev@example.com"}
+
+			alert := TestAlert()
+			if _, err := email.Send(context.Background(), alert); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			session := <-sessions
+			if got := strings.Join(session.commands, ","); got != "EHLO,MAIL,RCPT,RCPT,DATA,QUIT" {
+				t.Errorf("got commands %v", got)
+			}
+
+			message, err := mail.ReadMessage(strings.NewReader(session.data))
+			if err != nil {
+				t.Fatalf("invalid message: %v", err)
+			}
+			if to := message.Header.Get("To"); to != "ops@example.com, dev@example.com" {
+				t.Errorf("got To %q", to)
+			}
+			subject, _ := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+			if subject != "[Upstat] "+alert.Title() {
+				t.Errorf("got subject %q", subject)
+			}
+
+			mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+			if err != nil || mediaType != "multipart/alternative" {
+				t.Fatalf("got content type %v: %v", mediaType, err)
+			}
+			parts := multipart.NewReader(message.Body, params["boundary"])
+
+			plain, err := parts.NextPart()
+			if err != nil {
+				t.Fatal(err)
+			}
+			text, _ := io.ReadAll(plain)
+			if want := alert.Title() + "\n\n" + alert.Text() + "\n"; string(text) != want {
+				t.Errorf("got plain text %q, want %q", text, want)
+			}
+
+			html, err := parts.NextPart()
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(html)
+			if !strings.HasPrefix(html.Header.Get("Content-Type"), "text/html") || !strings.Contains(string(body), alert.Heartbeat.Message) {
+				t.Errorf("got html part %q", body)
+			}
+		})
+	}
+}
+
+func TestEmailSendRejectedRecipient(t *testing.T) {
+	email, sessions := fakeSMTP(t, map[string]string{"RCPT": "550 No such user"})
+	email.To = []string{"nobody@example.com"}
+
+	_, err := email.Send(context.Background(), TestAlert())
+	if err == nil || !strings.Contains(err.Error(), "nobody@example.com") {
+		t.Errorf("got error %v, want the rejected recipient", err)
+	}
+
+	session := <-sessions
+	if session.data != "" {
+		t.Errorf("sent a message without recipients: %q", session.data)
+	}
+}
@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type GotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+type Gotify struct {
+	Url   string `json:"url" validate:"required,url"`
+	Token string `json:"token" validate:"required"`
+}
+
+func init() {
+	Register("Gotify", func() Notifier { return new(Gotify) })
+}
+
+func (g *Gotify) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	priority := 8
+	switch alert.Type {
+	case "UP":
+		priority = 4
+	case "DEGRADED":
+		priority = 6
+	}
+
+	request, err := newJSONRequest(ctx, http.MethodPost, strings.TrimSuffix(g.Url, "/")+"/message", GotifyMessage{
+		Title:    alert.Title(),
+		Message:  alert.Text(),
+		Priority: priority,
+	})
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Gotify-Key", g.Token)
+
+	return do(request)
+}
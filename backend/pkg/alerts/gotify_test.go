@@ -0,0 +1,45 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGotifySend(t *testing.T) {
+	tests := []struct {
+		alertType string
+		priority  int
+	}{
+		{"DOWN", 8},
+		{"DEGRADED", 6},
+		{"UP", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alertType, func(t *testing.T) {
+			server, requests := standIn(t, http.StatusOK)
+
+			alert := TestAlert()
+			alert.Type = tt.alertType
+			gotify := &Gotify{Url: server.URL + "/", Token: "app-token"}
+			if _, err := gotify.Send(context.Background(), alert); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := <-requests
+			if request.method != http.MethodPost || request.uri != "/message" {
+				t.Errorf("got %v %v", request.method, request.uri)
+			}
+			if got := request.header.Get("X-Gotify-Key"); got != "app-token" {
+				t.Errorf("got token %q", got)
+			}
+
+			var message GotifyMessage
+			request.decode(t, &message)
+			if message.Title != alert.Title() || message.Message != alert.Text() || message.Priority != tt.priority {
+				t.Errorf("got message %+v, want priority %v", message, tt.priority)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type MatrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// Matrix sends a message to a room on a Matrix homeserver.
+type Matrix struct {
+	Url         string `json:"url" validate:"required,url"`
+	AccessToken string `json:"accessToken" validate:"required"`
+	RoomId      string `json:"roomId" validate:"required"`
+}
+
+func init() {
+	Register("Matrix", func() Notifier { return new(Matrix) })
+}
+
+func (m *Matrix) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	// The homeserver ignores a retried delivery with the same transaction
+	// id. Test alerts are not queued and must not be ignored.
+	txnId := fmt.Sprintf("upstat-%d", alert.DeliveryId)
+	if alert.DeliveryId == 0 {
+		txnId = fmt.Sprintf("upstat-test-%d", time.Now().UnixNano())
+	}
+	endpoint := fmt.Sprintf(
+		"%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(m.Url, "/"), url.PathEscape(m.RoomId), txnId,
+	)
+
+	request, err := newJSONRequest(ctx, http.MethodPut, endpoint, MatrixMessage{
+		MsgType:       "m.text",
+		Body:          alert.Title() + "\n\n" + alert.Text(),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: fmt.Sprintf("<strong>%s</strong><br>%s", html.EscapeString(alert.Title()), strings.ReplaceAll(html.EscapeString(alert.Text()), "\n", "<br>")),
+	})
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+m.AccessToken)
+
+	return do(request)
+}
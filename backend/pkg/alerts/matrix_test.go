@@ -0,0 +1,56 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMatrixSend(t *testing.T) {
+	server, requests := standIn(t, http.StatusOK)
+
+	alert := TestAlert()
+	alert.Heartbeat.Message = "<b>refused</b>"
+	alert.DeliveryId = 7
+	matrix := &Matrix{Url: server.URL + "/", AccessToken: "syt_secret", RoomId: "!room:example.org"}
+	if _, err := matrix.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := <-requests
+	if request.method != http.MethodPut || request.uri != "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/upstat-7" {
+		t.Errorf("got %v %v", request.method, request.uri)
+	}
+	if got := request.header.Get("Authorization"); got != "Bearer syt_secret" {
+		t.Errorf("got authorization %q", got)
+	}
+
+	var message MatrixMessage
+	request.decode(t, &message)
+	if message.MsgType != "m.text" || message.Body != alert.Title()+"\n\n"+alert.Text() || message.Format != "org.matrix.custom.html" {
+		t.Errorf("got message %+v", message)
+	}
+	if strings.Contains(message.FormattedBody, "<b>") || !strings.Contains(message.FormattedBody, "&lt;b&gt;refused&lt;/b&gt;") {
+		t.Errorf("got formatted body %q", message.FormattedBody)
+	}
+
+	// A retried delivery reuses the transaction id, a test alert never does.
+	if _, err := matrix.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retried := <-requests; retried.uri != request.uri {
+		t.Errorf("got %v for the retry, want %v", retried.uri, request.uri)
+	}
+
+	test := TestAlert()
+	for range 2 {
+		if _, err := matrix.Send(context.Background(), test); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	first, second := <-requests, <-requests
+	if !strings.Contains(first.uri, "/upstat-test-") || first.uri == second.uri {
+		t.Errorf("got %v and %v for test alerts", first.uri, second.uri)
+	}
+}
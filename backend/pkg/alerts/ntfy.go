@@ -0,0 +1,52 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const defaultNtfyUrl = "https://ntfy.sh"
+
+type NtfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags"`
+}
+
+type Ntfy struct {
+	Topic string `json:"topic" validate:"required"`
+	Url   string `json:"url" validate:"omitempty,url"`
+	Token string `json:"token"`
+}
+
+func init() {
+	Register("ntfy", func() Notifier { return new(Ntfy) })
+}
+
+func (n *Ntfy) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	baseUrl := strings.TrimSuffix(n.Url, "/")
+	if baseUrl == "" {
+		baseUrl = defaultNtfyUrl
+	}
+
+	message := NtfyMessage{Topic: n.Topic, Title: alert.Title(), Message: alert.Text(), Priority: 5, Tags: []string{"x"}}
+	switch alert.Type {
+	case "UP":
+		message.Priority, message.Tags = 3, []string{"white_check_mark"}
+	case "DEGRADED":
+		message.Priority, message.Tags = 4, []string{"warning"}
+	}
+
+	request, err := newJSONRequest(ctx, http.MethodPost, baseUrl, message)
+	if err != nil {
+		return nil, err
+	}
+	if n.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	return do(request)
+}
@@ -0,0 +1,53 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestNtfySend(t *testing.T) {
+	tests := []struct {
+		name          string
+		alertType     string
+		token         string
+		authorization string
+		priority      int
+		tags          []string
+	}{
+		{"down", "DOWN", "", "", 5, []string{"x"}},
+		{"degraded", "DEGRADED", "", "", 4, []string{"warning"}},
+		{"up with an access token", "UP", "tk_secret", "Bearer tk_secret", 3, []string{"white_check_mark"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := standIn(t, http.StatusOK)
+
+			alert := TestAlert()
+			alert.Type = tt.alertType
+			ntfy := &Ntfy{Topic: "upstat", Url: server.URL, Token: tt.token}
+			if _, err := ntfy.Send(context.Background(), alert); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := <-requests
+			if request.method != http.MethodPost || request.uri != "/" {
+				t.Errorf("got %v %v", request.method, request.uri)
+			}
+			if got := request.header.Get("Authorization"); got != tt.authorization {
+				t.Errorf("got authorization %q, want %q", got, tt.authorization)
+			}
+
+			var message NtfyMessage
+			request.decode(t, &message)
+			if message.Topic != "upstat" || message.Title != alert.Title() || message.Message != alert.Text() {
+				t.Errorf("got message %+v", message)
+			}
+			if message.Priority != tt.priority || !slices.Equal(message.Tags, tt.tags) {
+				t.Errorf("got priority %v and tags %v, want %v and %v", message.Priority, message.Tags, tt.priority, tt.tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+const defaultOpsgenieUrl = "https://api.opsgenie.com"
+
+type OpsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description"`
+	Priority    string            `json:"priority,omitempty"`
+	Source      string            `json:"source"`
+	Tags        []string          `json:"tags"`
+	Details     map[string]string `json:"details"`
+}
+
+// OpsgenieAction is the body of requests that act on an existing alert,
+// like closing or acknowledging it.
+type OpsgenieAction struct {
+	User   string `json:"user,omitempty"`
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+// Opsgenie creates an alert in Opsgenie when an incident opens and closes
+// it once the monitor leaves that status.
+type Opsgenie struct {
+	ApiKey   string `json:"apiKey" validate:"required"`
+	Url      string `json:"url" validate:"omitempty,url"`
+	Priority string `json:"priority" validate:"omitempty,oneof=P1 P2 P3 P4 P5"`
+}
+
+func init() {
+	Register("Opsgenie", func() Notifier { return new(Opsgenie) })
+}
+
+func (o *Opsgenie) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	baseUrl := o.baseUrl()
+
+	switch alert.Event {
+	case EventAcknowledged:
+		return o.post(ctx, opsgenieAlertUrl(baseUrl, alert.Incident, "acknowledge"), OpsgenieAction{User: alert.User, Source: "Upstat", Note: alert.Title()})
+	case EventNote:
+		return o.post(ctx, opsgenieAlertUrl(baseUrl, alert.Incident, "notes"), OpsgenieAction{User: alert.User, Source: "Upstat", Note: alert.Note.Text})
+	}
+
+	if alert.Resolved != nil {
+		response, err := o.Resolve(ctx, alert.Resolved)
+		if err != nil || alert.Type == "UP" {
+			return response, err
+		}
+	}
+
+	if alert.Type == "UP" || alert.Incident == nil {
+		return nil, nil
+	}
+
+	return o.post(ctx, baseUrl+"/v2/alerts", OpsgenieAlert{
+		Message:     truncate(alert.Title(), 130),
+		Alias:       DedupKey(alert.Incident),
+		Description: alert.Text(),
+		Priority:    o.Priority,
+		Source:      "Upstat",
+		Tags:        []string{"upstat", strings.ToLower(alert.Type)},
+		Details: map[string]string{
+			"monitor":     alert.Monitor.Name,
+			"url":         alert.Monitor.Url,
+			"status_code": alert.Heartbeat.StatusCode,
+			"latency":     fmt.Sprintf("%vms", alert.Heartbeat.Latency),
+		},
+	})
+}
+
+// Resolve closes the alert created for the incident.
+func (o *Opsgenie) Resolve(ctx context.Context, incident *models.Incident) (*Response, error) {
+	return o.post(ctx, opsgenieAlertUrl(o.baseUrl(), incident, "close"), OpsgenieAction{Source: "Upstat", Note: "Resolved by Upstat"})
+}
+
+func (o *Opsgenie) baseUrl() string {
+	if o.Url == "" {
+		return defaultOpsgenieUrl
+	}
+
+	return strings.TrimSuffix(o.Url, "/")
+}
+
+// opsgenieAlertUrl is the endpoint of an action on the alert created for
+// the incident.
+func opsgenieAlertUrl(baseUrl string, incident *models.Incident, action string) string {
+	return fmt.Sprintf("%s/v2/alerts/%s/%s?identifierType=alias", baseUrl, url.PathEscape(DedupKey(incident)), action)
+}
+
+func (o *Opsgenie) post(ctx context.Context, endpoint string, payload any) (*Response, error) {
+	request, err := newJSONRequest(ctx, http.MethodPost, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "GenieKey "+o.ApiKey)
+
+	return do(request)
+}
+
+func truncate(value string, length int) string {
+	if runes := []rune(value); len(runes) > length {
+		return string(runes[:length])
+	}
+
+	return value
+}
@@ -0,0 +1,78 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func TestOpsgenieSend(t *testing.T) {
+	server, requests := standIn(t, http.StatusAccepted)
+	opsgenie := &Opsgenie{ApiKey: "api-key", Url: server.URL + "/", Priority: "P2"}
+
+	down := TestAlert()
+	down.Incident = &models.Incident{ID: 2, MonitorId: 1, Type: "DOWN"}
+	acknowledged := TestAlert()
+	acknowledged.Event, acknowledged.User = EventAcknowledged, "admin"
+	acknowledged.Incident = down.Incident
+	note := TestAlert()
+	note.Event, note.User, note.Note = EventNote, "admin", &models.IncidentNote{Text: "Restarted"}
+	note.Incident = down.Incident
+	up := TestResolution(down)
+
+	steps := []struct {
+		name  string
+		alert *Alert
+		uri   string
+		user  string
+		note  string
+	}{
+		{"down creates", down, "/v2/alerts", "", ""},
+		{"acknowledge", acknowledged, "/v2/alerts/upstat-monitor-1-incident-2/acknowledge?identifierType=alias", "admin", acknowledged.Title()},
+		{"note", note, "/v2/alerts/upstat-monitor-1-incident-2/notes?identifierType=alias", "admin", "Restarted"},
+		{"up closes", up, "/v2/alerts/upstat-monitor-1-incident-2/close?identifierType=alias", "", "Resolved by Upstat"},
+	}
+
+	for _, step := range steps {
+		if _, err := opsgenie.Send(context.Background(), step.alert); err != nil {
+			t.Fatalf("%v: unexpected error: %v", step.name, err)
+		}
+
+		request := <-requests
+		if request.method != http.MethodPost || request.uri != step.uri {
+			t.Errorf("%v: got %v %v, want %v", step.name, request.method, request.uri, step.uri)
+		}
+		if got := request.header.Get("Authorization"); got != "GenieKey api-key" {
+			t.Errorf("%v: got authorization %q", step.name, got)
+		}
+
+		if step.uri == "/v2/alerts" {
+			var alert OpsgenieAlert
+			request.decode(t, &alert)
+			if alert.Alias != "upstat-monitor-1-incident-2" || alert.Priority != "P2" || alert.Message != down.Title() {
+				t.Errorf("%v: got %+v", step.name, alert)
+			}
+			continue
+		}
+
+		var action OpsgenieAction
+		request.decode(t, &action)
+		if action.User != step.user || action.Note != step.note || action.Source != "Upstat" {
+			t.Errorf("%v: got %+v", step.name, action)
+		}
+	}
+
+	if len(requests) != 0 {
+		t.Errorf("got %d unexpected requests", len(requests))
+	}
+}
+
+func TestOpsgenieRetryAfter(t *testing.T) {
+	server := rateLimited(t, "5")
+
+	_, err := (&Opsgenie{ApiKey: "api-key", Url: server.URL}).Send(context.Background(), TestAlert())
+	assertRetryAfter(t, err, 5*time.Second)
+}
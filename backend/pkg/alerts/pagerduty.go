@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"context"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+const defaultPagerDutyUrl = "https://events.pagerduty.com/v2/enqueue"
+
+type PagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp"`
+	Component     string         `json:"component"`
+	CustomDetails map[string]any `json:"custom_details"`
+}
+
+type PagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *PagerDutyPayload `json:"payload,omitempty"`
+}
+
+// PagerDuty sends events to the PagerDuty Events API v2. An incident
+// triggers an event that is resolved once the monitor leaves that status.
+type PagerDuty struct {
+	RoutingKey string `json:"routingKey" validate:"required"`
+	Url        string `json:"url" validate:"omitempty,url"`
+}
+
+func init() {
+	Register("PagerDuty", func() Notifier { return new(PagerDuty) })
+}
+
+func (p *PagerDuty) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	url := p.endpoint()
+
+	switch alert.Event {
+	case EventAcknowledged:
+		return postJSON(ctx, url, PagerDutyEvent{RoutingKey: p.RoutingKey, EventAction: "acknowledge", DedupKey: DedupKey(alert.Incident)})
+	case EventNote:
+		// The Events API has no way to add notes to an incident.
+		return nil, nil
+	}
+
+	if alert.Resolved != nil {
+		response, err := p.Resolve(ctx, alert.Resolved)
+		if err != nil || alert.Type == "UP" {
+			return response, err
+		}
+	}
+
+	if alert.Type == "UP" || alert.Incident == nil {
+		return nil, nil
+	}
+
+	severity := "critical"
+	if alert.Type == "DEGRADED" {
+		severity = "warning"
+	}
+
+	return postJSON(ctx, url, PagerDutyEvent{
+		RoutingKey:  p.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    DedupKey(alert.Incident),
+		Payload: &PagerDutyPayload{
+			Summary:   alert.Title(),
+			Source:    alert.Monitor.Url,
+			Severity:  severity,
+			Timestamp: alert.Time.UTC().Format(time.RFC3339),
+			Component: alert.Monitor.Name,
+			CustomDetails: map[string]any{
+				"status_code": alert.Heartbeat.StatusCode,
+				"latency":     alert.Heartbeat.Latency,
+				"message":     alert.Heartbeat.Message,
+			},
+		},
+	})
+}
+
+// Resolve resolves the event triggered for the incident.
+func (p *PagerDuty) Resolve(ctx context.Context, incident *models.Incident) (*Response, error) {
+	return postJSON(ctx, p.endpoint(), PagerDutyEvent{RoutingKey: p.RoutingKey, EventAction: "resolve", DedupKey: DedupKey(incident)})
+}
+
+func (p *PagerDuty) endpoint() string {
+	if p.Url == "" {
+		return defaultPagerDutyUrl
+	}
+
+	return p.Url
+}
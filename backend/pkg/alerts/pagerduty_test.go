@@ -0,0 +1,87 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func TestPagerDutySend(t *testing.T) {
+	server, requests := standIn(t, http.StatusAccepted)
+	pagerDuty := &PagerDuty{RoutingKey: "routing-key", Url: server.URL}
+
+	down := TestAlert()
+	down.Incident = &models.Incident{ID: 2, MonitorId: 1, Type: "DOWN"}
+	degraded := TestAlert()
+	degraded.Type = "DEGRADED"
+	degraded.Resolved = down.Incident
+	degraded.Incident = &models.Incident{ID: 3, MonitorId: 1, Type: "DEGRADED"}
+	acknowledged := TestAlert()
+	acknowledged.Event, acknowledged.User = EventAcknowledged, "admin"
+	acknowledged.Incident = degraded.Incident
+	up := TestResolution(degraded)
+	up.Resolved = degraded.Incident
+
+	// Each step lists the events it sends as action and dedup key, and the
+	// severity of the event it triggers.
+	steps := []struct {
+		name     string
+		alert    *Alert
+		events   [][2]string
+		severity string
+	}{
+		{"down triggers", down, [][2]string{{"trigger", "upstat-monitor-1-incident-2"}}, "critical"},
+		{"degraded resolves and triggers", degraded, [][2]string{
+			{"resolve", "upstat-monitor-1-incident-2"},
+			{"trigger", "upstat-monitor-1-incident-3"},
+		}, "warning"},
+		{"acknowledge", acknowledged, [][2]string{{"acknowledge", "upstat-monitor-1-incident-3"}}, ""},
+		{"up resolves", up, [][2]string{{"resolve", "upstat-monitor-1-incident-3"}}, ""},
+	}
+
+	for _, step := range steps {
+		if _, err := pagerDuty.Send(context.Background(), step.alert); err != nil {
+			t.Fatalf("%v: unexpected error: %v", step.name, err)
+		}
+
+		for _, want := range step.events {
+			request := <-requests
+			var event PagerDutyEvent
+			request.decode(t, &event)
+			if event.RoutingKey != "routing-key" || event.EventAction != want[0] || event.DedupKey != want[1] {
+				t.Errorf("%v: got %+v, want %v", step.name, event, want)
+			}
+			if want[0] != "trigger" && event.Payload != nil {
+				t.Errorf("%v: got payload %+v for %v", step.name, event.Payload, want[0])
+			}
+			if want[0] == "trigger" && (event.Payload == nil || event.Payload.Severity != step.severity) {
+				t.Errorf("%v: got payload %+v, want severity %v", step.name, event.Payload, step.severity)
+			}
+		}
+	}
+
+	if len(requests) != 0 {
+		t.Errorf("got %d unexpected events", len(requests))
+	}
+}
+
+func TestPagerDutyNote(t *testing.T) {
+	server, requests := standIn(t, http.StatusAccepted)
+
+	alert := TestAlert()
+	alert.Event, alert.Note = EventNote, &models.IncidentNote{Text: "Restarted"}
+	response, err := (&PagerDuty{RoutingKey: "routing-key", Url: server.URL}).Send(context.Background(), alert)
+	if response != nil || err != nil || len(requests) != 0 {
+		t.Errorf("got %v %v and %d events for a note", response, err, len(requests))
+	}
+}
+
+func TestPagerDutyRetryAfter(t *testing.T) {
+	server := rateLimited(t, "30")
+
+	_, err := (&PagerDuty{RoutingKey: "routing-key", Url: server.URL}).Send(context.Background(), TestAlert())
+	assertRetryAfter(t, err, 30*time.Second)
+}
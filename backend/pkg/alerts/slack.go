@@ -0,0 +1,95 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+)
+
+type SlackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Fields   []SlackText `json:"fields,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+type SlackWebhookMessage struct {
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+type Slack struct {
+	WebhookUrl string `json:"webhookUrl" validate:"required,url"`
+}
+
+func init() {
+	Register("Slack", func() Notifier { return new(Slack) })
+}
+
+func (s *Slack) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	return postJSON(ctx, s.WebhookUrl, SlackAlertMessage(alert))
+}
+
+func SlackAlertMessage(alert *Alert) SlackWebhookMessage {
+	if alert.Event != "" {
+		return slackEventMessage(alert)
+	}
+
+	emoji := ":x:"
+	switch alert.Type {
+	case "UP":
+		emoji = ":white_check_mark:"
+	case "DEGRADED":
+		emoji = ":warning:"
+	}
+
+	fields := []SlackText{
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Monitor*\n%v", alert.Monitor.Name)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*URL*\n%v", alert.Monitor.Url)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Status Code*\n%v", alert.Heartbeat.StatusCode)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Latency*\n%vms", alert.Heartbeat.Latency)},
+	}
+	if alert.Duration > 0 {
+		fields = append(fields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*Outage duration*\n%v", FormatDuration(alert.Duration))})
+	}
+
+	footer := alert.Time.UTC().Format("2006-01-02 15:04:05 UTC")
+	if alert.Type != "UP" && alert.Heartbeat.Message != "" {
+		footer = fmt.Sprintf("%v | %v", alert.Heartbeat.Message, footer)
+	}
+
+	return SlackWebhookMessage{
+		Text: fmt.Sprintf("%s %s", emoji, alert.Title()),
+		Blocks: []SlackBlock{
+			{Type: "header", Text: &SlackText{Type: "plain_text", Text: fmt.Sprintf("%s %s", emoji, alert.Title()), Emoji: true}},
+			{Type: "section", Fields: fields},
+			{Type: "context", Elements: []SlackText{{Type: "mrkdwn", Text: footer}}},
+		},
+	}
+}
+
+func slackEventMessage(alert *Alert) SlackWebhookMessage {
+	emoji := ":memo:"
+	if alert.Event == EventAcknowledged {
+		emoji = ":eyes:"
+	}
+
+	var fields []SlackText
+	for _, detail := range alert.EventDetails() {
+		fields = append(fields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*%v*\n%v", detail[0], detail[1])})
+	}
+
+	return SlackWebhookMessage{
+		Text: fmt.Sprintf("%s %s", emoji, alert.Title()),
+		Blocks: []SlackBlock{
+			{Type: "header", Text: &SlackText{Type: "plain_text", Text: fmt.Sprintf("%s %s", emoji, alert.Title()), Emoji: true}},
+			{Type: "section", Fields: fields},
+			{Type: "context", Elements: []SlackText{{Type: "mrkdwn", Text: alert.Time.UTC().Format("2006-01-02 15:04:05 UTC")}}},
+		},
+	}
+}
@@ -0,0 +1,145 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func TestSlackSend(t *testing.T) {
+	var received SlackWebhookMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %v with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	alert := TestAlert()
+	response, err := (&Slack{WebhookUrl: server.URL}).Send(context.Background(), alert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK || response.Body != "ok" {
+		t.Errorf("got response %v %q", response.StatusCode, response.Body)
+	}
+
+	if !strings.HasPrefix(received.Text, ":x: ") || !strings.Contains(received.Text, alert.Monitor.Name) {
+		t.Errorf("got text %q", received.Text)
+	}
+
+	types := []string{}
+	for _, block := range received.Blocks {
+		types = append(types, block.Type)
+	}
+	if strings.Join(types, ",") != "header,section,context" {
+		t.Fatalf("got blocks %v", types)
+	}
+	if header := received.Blocks[0].Text; header == nil || header.Type != "plain_text" || header.Text != received.Text {
+		t.Errorf("got header %+v", header)
+	}
+	if fields := received.Blocks[1].Fields; len(fields) != 4 || fields[0].Type != "mrkdwn" || fields[0].Text != "*Monitor*\n"+alert.Monitor.Name {
+		t.Errorf("got fields %+v", fields)
+	}
+	if footer := received.Blocks[2].Elements; len(footer) != 1 || !strings.HasPrefix(footer[0].Text, alert.Heartbeat.Message+" | ") {
+		t.Errorf("got footer %+v", footer)
+	}
+}
+
+func TestSlackSendStatusError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		temporary  bool
+	}{
+		{"invalid payload", http.StatusBadRequest, "", false},
+		{"removed webhook", http.StatusNotFound, "", false},
+		{"rate limited", http.StatusTooManyRequests, "30", true},
+		{"server error", http.StatusInternalServerError, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte("no_service"))
+			}))
+			defer server.Close()
+
+			response, err := (&Slack{WebhookUrl: server.URL}).Send(context.Background(), TestAlert())
+
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("got error %v, want a StatusError", err)
+			}
+			if statusErr.StatusCode != tt.status || statusErr.Body != "no_service" || statusErr.Temporary() != tt.temporary {
+				t.Errorf("got %v %q temporary %v", statusErr.StatusCode, statusErr.Body, statusErr.Temporary())
+			}
+			if tt.retryAfter != "" && statusErr.RetryAfter.Seconds() != 30 {
+				t.Errorf("got retry after %v", statusErr.RetryAfter)
+			}
+			if response == nil || response.StatusCode != tt.status {
+				t.Errorf("got response %+v", response)
+			}
+		})
+	}
+}
+
+func TestSlackAlertMessageEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  string
+		note   *models.IncidentNote
+		fields []string
+	}{
+		{"acknowledged", EventAcknowledged, nil, []string{"Monitor", "URL", "Incident", "Acknowledged by"}},
+		{"note", EventNote, &models.IncidentNote{Text: "Rolling back"}, []string{"Monitor", "URL", "Incident", "Note by", "Note"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := TestAlert()
+			alert.Event, alert.User, alert.Note = tt.event, "alice", tt.note
+
+			message := SlackAlertMessage(alert)
+			if !strings.Contains(message.Text, alert.Title()) {
+				t.Errorf("got text %q", message.Text)
+			}
+
+			names := []string{}
+			values := map[string]string{}
+			for _, field := range message.Blocks[1].Fields {
+				name, value, _ := strings.Cut(strings.TrimPrefix(field.Text, "*"), "*\n")
+				names = append(names, name)
+				values[name] = value
+			}
+			if strings.Join(names, ",") != strings.Join(tt.fields, ",") {
+				t.Fatalf("got fields %v, want %v", names, tt.fields)
+			}
+			if user := values[tt.fields[3]]; user != "alice" {
+				t.Errorf("got user %q", user)
+			}
+			if tt.note != nil && values["Note"] != tt.note.Text {
+				t.Errorf("got note %q", values["Note"])
+			}
+
+			if footer := message.Blocks[2].Elements[0].Text; strings.Contains(footer, alert.Heartbeat.Message) {
+				t.Errorf("got heartbeat message in footer %q", footer)
+			}
+		})
+	}
+}
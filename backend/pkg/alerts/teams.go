@@ -0,0 +1,101 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+)
+
+type TeamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type TeamsCardElement struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	Weight string      `json:"weight,omitempty"`
+	Size   string      `json:"size,omitempty"`
+	Color  string      `json:"color,omitempty"`
+	Wrap   bool        `json:"wrap,omitempty"`
+	Facts  []TeamsFact `json:"facts,omitempty"`
+}
+
+type TeamsCard struct {
+	Schema  string             `json:"$schema"`
+	Type    string             `json:"type"`
+	Version string             `json:"version"`
+	Body    []TeamsCardElement `json:"body"`
+}
+
+type TeamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     TeamsCard `json:"content"`
+}
+
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// Teams posts an Adaptive Card to a Microsoft Teams incoming webhook or
+// workflow URL.
+type Teams struct {
+	WebhookUrl string `json:"webhookUrl" validate:"required,url"`
+}
+
+func init() {
+	Register("Teams", func() Notifier { return new(Teams) })
+}
+
+func (t *Teams) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	return postJSON(ctx, t.WebhookUrl, TeamsAlertMessage(alert))
+}
+
+func TeamsAlertMessage(alert *Alert) TeamsMessage {
+	color := "Attention"
+	switch alert.Type {
+	case "UP":
+		color = "Good"
+	case "DEGRADED":
+		color = "Warning"
+	}
+
+	var facts []TeamsFact
+	if alert.Event != "" {
+		color = "Accent"
+		for _, detail := range alert.EventDetails() {
+			facts = append(facts, TeamsFact{Title: detail[0], Value: detail[1]})
+		}
+	} else {
+		facts = []TeamsFact{
+			{Title: "Monitor", Value: alert.Monitor.Name},
+			{Title: "URL", Value: alert.Monitor.Url},
+			{Title: "Status Code", Value: alert.Heartbeat.StatusCode},
+			{Title: "Latency", Value: fmt.Sprintf("%vms", alert.Heartbeat.Latency)},
+		}
+		if alert.Type != "UP" && alert.Heartbeat.Message != "" {
+			facts = append(facts, TeamsFact{Title: "Reason", Value: alert.Heartbeat.Message})
+		}
+		if alert.Duration > 0 {
+			facts = append(facts, TeamsFact{Title: "Outage duration", Value: FormatDuration(alert.Duration)})
+		}
+	}
+
+	return TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: TeamsCard{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body: []TeamsCardElement{
+						{Type: "TextBlock", Text: alert.Title(), Weight: "Bolder", Size: "Medium", Color: color, Wrap: true},
+						{Type: "FactSet", Facts: facts},
+					},
+				},
+			},
+		},
+	}
+}
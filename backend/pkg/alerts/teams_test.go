@@ -0,0 +1,57 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestTeamsSend(t *testing.T) {
+	tests := []struct {
+		name      string
+		alertType string
+		event     string
+		color     string
+		facts     int
+	}{
+		{"down", "DOWN", "", "Attention", 5},
+		{"degraded", "DEGRADED", "", "Warning", 5},
+		{"up", "UP", "", "Good", 4},
+		{"acknowledged", "DOWN", EventAcknowledged, "Accent", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := standIn(t, http.StatusOK)
+
+			alert := TestAlert()
+			alert.Type, alert.Event = tt.alertType, tt.event
+			if _, err := (&Teams{WebhookUrl: server.URL}).Send(context.Background(), alert); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := <-requests
+			if request.method != http.MethodPost || request.header.Get("Content-Type") != "application/json" {
+				t.Errorf("got %v with content type %q", request.method, request.header.Get("Content-Type"))
+			}
+
+			var message TeamsMessage
+			request.decode(t, &message)
+			if message.Type != "message" || len(message.Attachments) != 1 {
+				t.Fatalf("got message %+v", message)
+			}
+			attachment := message.Attachments[0]
+			if attachment.ContentType != "application/vnd.microsoft.card.adaptive" || attachment.Content.Type != "AdaptiveCard" || len(attachment.Content.Body) != 2 {
+				t.Fatalf("got attachment %+v", attachment)
+			}
+
+			title, facts := attachment.Content.Body[0], attachment.Content.Body[1]
+			if title.Type != "TextBlock" || title.Text != alert.Title() || title.Color != tt.color {
+				t.Errorf("got title %+v, want color %v", title, tt.color)
+			}
+			if facts.Type != "FactSet" || len(facts.Facts) != tt.facts {
+				t.Errorf("got facts %+v", facts)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const defaultTelegramUrl = "https://api.telegram.org"
+
+type TelegramMessage struct {
+	ChatId string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type Telegram struct {
+	BotToken string `json:"botToken" validate:"required"`
+	ChatId   string `json:"chatId" validate:"required"`
+	Url      string `json:"url" validate:"omitempty,url"`
+}
+
+func init() {
+	Register("Telegram", func() Notifier { return new(Telegram) })
+}
+
+func (t *Telegram) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	baseUrl := strings.TrimSuffix(t.Url, "/")
+	if baseUrl == "" {
+		baseUrl = defaultTelegramUrl
+	}
+
+	return postJSON(ctx, fmt.Sprintf("%s/bot%s/sendMessage", baseUrl, t.BotToken), TelegramMessage{
+		ChatId: t.ChatId,
+		Text:   alert.Title() + "\n\n" + alert.Text(),
+	})
+}
@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTelegramSendErrorHidesToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	telegram := &Telegram{BotToken: "123456:secret-token", ChatId: "42", Url: server.URL}
+	_, err := telegram.Send(context.Background(), TestAlert())
+	if err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error contains the bot token: %v", err)
+	}
+}
+
+func TestTelegramSend(t *testing.T) {
+	server, requests := standIn(t, http.StatusOK)
+
+	alert := TestAlert()
+	telegram := &Telegram{BotToken: "123456:secret-token", ChatId: "-1001", Url: server.URL + "/"}
+	if _, err := telegram.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := <-requests
+	if request.method != http.MethodPost || request.uri != "/bot123456:secret-token/sendMessage" {
+		t.Errorf("got %v %v", request.method, request.uri)
+	}
+
+	var message TelegramMessage
+	request.decode(t, &message)
+	if message.ChatId != "-1001" || message.Text != alert.Title()+"\n\n"+alert.Text() {
+		t.Errorf("got message %+v", message)
+	}
+}
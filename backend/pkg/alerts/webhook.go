@@ -0,0 +1,140 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of the request body,
+// keyed with the webhook secret and prefixed with "sha256=".
+const SignatureHeader = "X-Upstat-Signature"
+
+type Webhook struct {
+	Url     string            `json:"url" validate:"required,url"`
+	Method  string            `json:"method" validate:"omitempty,oneof=POST PUT PATCH"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body" validate:"omitempty,template"`
+	Secret  string            `json:"secret"`
+}
+
+type WebhookMonitor struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Url    string `json:"url"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// WebhookPayload is the body posted when the webhook has no body template.
+type WebhookPayload struct {
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Text      string               `json:"text"`
+	Monitor   WebhookMonitor       `json:"monitor"`
+	Heartbeat *models.Heartbeat    `json:"heartbeat"`
+	Incident  *models.Incident     `json:"incident"`
+	Duration  int64                `json:"duration,omitempty"`
+	Reminder  int                  `json:"reminder,omitempty"`
+	Event     string               `json:"event,omitempty"`
+	User      string               `json:"user,omitempty"`
+	Note      *models.IncidentNote `json:"note,omitempty"`
+	Time      time.Time            `json:"time"`
+}
+
+func init() {
+	Register("Webhook", func() Notifier { return new(Webhook) })
+}
+
+// WebhookTemplateFuncs are available to webhook body templates in
+// addition to the fields and methods of the Alert.
+var WebhookTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// ParseWebhookTemplate parses a webhook body template.
+func ParseWebhookTemplate(body string) (*template.Template, error) {
+	return template.New("webhook").Funcs(WebhookTemplateFuncs).Parse(body)
+}
+
+func (w *Webhook) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	body, err := w.render(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(w.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, w.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range w.Headers {
+		request.Header.Set(key, value)
+	}
+
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		request.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return do(request)
+}
+
+func (w *Webhook) render(alert *Alert) ([]byte, error) {
+	if w.Body == "" {
+		payload := WebhookPayload{
+			Type:  alert.Type,
+			Title: alert.Title(),
+			Text:  alert.Text(),
+			Monitor: WebhookMonitor{
+				ID:     alert.Monitor.ID,
+				Name:   alert.Monitor.Name,
+				Url:    alert.Monitor.Url,
+				Type:   alert.Monitor.Type,
+				Status: alert.Monitor.Status,
+			},
+			Heartbeat: alert.Heartbeat,
+			Incident:  alert.Incident,
+			Duration:  int64(alert.Duration.Seconds()),
+			Reminder:  alert.Reminder,
+			Event:     alert.Event,
+			User:      alert.User,
+			Note:      alert.Note,
+			Time:      alert.Time,
+		}
+
+		return json.Marshal(payload)
+	}
+
+	tmpl, err := ParseWebhookTemplate(w.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, alert); err != nil {
+		return nil, fmt.Errorf("failed to render body template: %w", err)
+	}
+
+	return body.Bytes(), nil
+}
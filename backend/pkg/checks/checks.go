@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// Result is the outcome of a single probe, regardless of the monitor type.
+type Result struct {
+	Status     string
+	StatusCode string
+	Latency    int
+	Message    string
+}
+
+// Checker probes a monitor target. Failures are reported through the
+// returned Result rather than as errors.
+type Checker interface {
+	Check(ctx context.Context, monitor *models.Monitor) *Result
+}
+
+var registry = map[string]Checker{}
+
+// Register makes a checker available for monitors of the given type.
+func Register(monitorType string, checker Checker) {
+	registry[monitorType] = checker
+}
+
+func Get(monitorType string) (Checker, bool) {
+	checker, ok := registry[monitorType]
+	return checker, ok
+}
+
+// Types returns the registered monitor types in sorted order.
+func Types() []string {
+	types := make([]string, 0, len(registry))
+	for monitorType := range registry {
+		types = append(types, monitorType)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// Run probes the monitor with the checker registered for its type.
+func Run(ctx context.Context, monitor *models.Monitor) *Result {
+	checker, ok := Get(monitor.Type)
+	if !ok {
+		return Down("error", fmt.Sprintf("unknown monitor type %q", monitor.Type))
+	}
+
+	return checker.Check(ctx, monitor)
+}
+
+func Down(statusCode, message string) *Result {
+	return &Result{
+		Status:     "red",
+		StatusCode: statusCode,
+		Message:    message,
+	}
+}
+
+// Timeout returns how long a single probe of the monitor may take.
+func Timeout(monitor *models.Monitor) time.Duration {
+	if monitor.Timeout > 0 {
+		return time.Duration(monitor.Timeout) * time.Second
+	}
+
+	return defaultTimeout
+}
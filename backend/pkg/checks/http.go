@@ -0,0 +1,210 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+const defaultMaxBodySize = 1 << 20
+
+var defaultAcceptedStatusCodes = []string{"200-299"}
+
+func init() {
+	Register("http", &HTTP{
+		client: &http.Client{},
+		noRedirects: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	})
+}
+
+// HTTP checks the response to a request. Monitors that don't follow
+// redirects get the redirect response itself, so that 3xx status codes
+// can be accepted.
+type HTTP struct {
+	client      *http.Client
+	noRedirects *http.Client
+}
+
+func (h *HTTP) Check(ctx context.Context, monitor *models.Monitor) *Result {
+	ctx, cancel := context.WithTimeout(ctx, Timeout(monitor))
+	defer cancel()
+
+	request, err := newRequest(ctx, monitor)
+	if err != nil {
+		return Down("error", fmt.Sprintf("invalid request: %v", err))
+	}
+
+	client := h.client
+	if !monitor.FollowRedirects {
+		client = h.noRedirects
+	}
+
+	startTime := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		return Down("error", fmt.Sprintf("unable to ping: %v", err))
+	}
+	defer response.Body.Close()
+
+	result := &Result{
+		Status:     "green",
+		StatusCode: strconv.Itoa(response.StatusCode),
+		Latency:    int(time.Since(startTime).Milliseconds()),
+		Message:    http.StatusText(response.StatusCode),
+	}
+
+	acceptedStatusCodes := monitor.AcceptedStatusCodes
+	if len(acceptedStatusCodes) == 0 {
+		acceptedStatusCodes = defaultAcceptedStatusCodes
+	}
+	if !statusCodeAccepted(response.StatusCode, acceptedStatusCodes) {
+		result.Status = "red"
+		result.Message = fmt.Sprintf("status code %d is not one of the accepted status codes [%s]", response.StatusCode, strings.Join(acceptedStatusCodes, ", "))
+		return result
+	}
+
+	if monitor.Keyword == "" && len(monitor.JsonAssertions) == 0 {
+		return result
+	}
+
+	maxBodySize := int64(defaultMaxBodySize)
+	if monitor.MaxBodySize > 0 {
+		maxBodySize = int64(monitor.MaxBodySize)
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		result.Status = "red"
+		result.Message = fmt.Sprintf("unable to read response body: %v", err)
+		return result
+	}
+
+	if monitor.Keyword != "" {
+		if message := checkKeyword(monitor, body); message != "" {
+			result.Status = "red"
+			result.Message = message
+			return result
+		}
+	}
+
+	if len(monitor.JsonAssertions) > 0 {
+		if message := checkJsonAssertions(monitor.JsonAssertions, body); message != "" {
+			result.Status = "red"
+			result.Message = message
+		}
+	}
+
+	return result
+}
+
+// ParseStatusCodeRange parses a single status code such as "301" or an
+// inclusive range such as "200-299".
+func ParseStatusCodeRange(value string) (int, int, error) {
+	low, high, isRange := strings.Cut(strings.TrimSpace(value), "-")
+
+	from, err := strconv.Atoi(strings.TrimSpace(low))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid status code %q", value)
+	}
+	to := from
+	if isRange {
+		to, err = strconv.Atoi(strings.TrimSpace(high))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid status code %q", value)
+		}
+	}
+
+	if from < 100 || to > 599 || from > to {
+		return 0, 0, fmt.Errorf("invalid status code range %q", value)
+	}
+
+	return from, to, nil
+}
+
+func statusCodeAccepted(statusCode int, acceptedStatusCodes []string) bool {
+	for _, accepted := range acceptedStatusCodes {
+		from, to, err := ParseStatusCodeRange(accepted)
+		if err != nil {
+			continue
+		}
+		if statusCode >= from && statusCode <= to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkKeyword returns a description of the failed keyword assertion, or an
+// empty string when the body satisfies it.
+func checkKeyword(monitor *models.Monitor, body []byte) string {
+	var found bool
+	if monitor.KeywordRegex {
+		pattern, err := regexp.Compile(monitor.Keyword)
+		if err != nil {
+			return fmt.Sprintf("invalid keyword pattern %q: %v", monitor.Keyword, err)
+		}
+		found = pattern.Match(body)
+	} else {
+		found = bytes.Contains(body, []byte(monitor.Keyword))
+	}
+
+	kind := "keyword"
+	if monitor.KeywordRegex {
+		kind = "pattern"
+	}
+
+	switch {
+	case monitor.KeywordInvert && found:
+		return fmt.Sprintf("forbidden %s %q was found in the response body", kind, monitor.Keyword)
+	case !monitor.KeywordInvert && !found:
+		return fmt.Sprintf("required %s %q was not found in the first %d bytes of the response body", kind, monitor.Keyword, len(body))
+	default:
+		return ""
+	}
+}
+
+func newRequest(ctx context.Context, monitor *models.Monitor) (*http.Request, error) {
+	method := strings.ToUpper(monitor.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if monitor.Body != "" {
+		body = strings.NewReader(monitor.Body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, monitor.Url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if monitor.Body != "" && monitor.ContentType != "" {
+		request.Header.Set("Content-Type", monitor.ContentType)
+	}
+
+	for key, value := range monitor.Headers {
+		request.Header.Set(key, value)
+	}
+
+	switch monitor.AuthMethod {
+	case "basic":
+		request.SetBasicAuth(monitor.AuthUsername, monitor.AuthPassword)
+	case "bearer":
+		request.Header.Set("Authorization", "Bearer "+monitor.AuthToken)
+	}
+
+	return request, nil
+}
@@ -0,0 +1,49 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func TestHTTPCheckRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker, _ := Get("http")
+
+	tests := []struct {
+		name                string
+		followRedirects     bool
+		acceptedStatusCodes []string
+		status              string
+		statusCode          string
+	}{
+		{"followed", true, nil, "green", "200"},
+		{"followed past an accepted redirect", true, []string{"300-399"}, "red", "200"},
+		{"not followed", false, []string{"301"}, "green", "301"},
+		{"not followed and not accepted", false, nil, "red", "301"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &models.Monitor{
+				Url: server.URL + "/old", Method: "GET", FollowRedirects: tt.followRedirects, AcceptedStatusCodes: tt.acceptedStatusCodes,
+			}
+
+			result := checker.Check(context.Background(), monitor)
+			if result.Status != tt.status || result.StatusCode != tt.statusCode {
+				t.Errorf("got %v %v (%v), want %v %v", result.Status, result.StatusCode, result.Message, tt.status, tt.statusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+// checkJsonAssertions returns a description of the first assertion the body
+// does not satisfy, or an empty string when all of them hold.
+func checkJsonAssertions(assertions []models.JsonAssertion, body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Sprintf("response body is not valid JSON: %v", err)
+	}
+
+	for _, assertion := range assertions {
+		if message := evaluateJsonAssertion(assertion, document); message != "" {
+			return message
+		}
+	}
+
+	return ""
+}
+
+func evaluateJsonAssertion(assertion models.JsonAssertion, document any) string {
+	expression := fmt.Sprintf("%s %s %q", assertion.Path, assertion.Operator, assertion.Value)
+	if assertion.Operator == "exists" {
+		expression = fmt.Sprintf("%s exists", assertion.Path)
+	}
+
+	actual, err := lookupJsonPath(document, assertion.Path)
+	if err != nil {
+		return fmt.Sprintf("json assertion failed: %s (%v)", expression, err)
+	}
+
+	ok, err := compareJsonValue(actual, assertion.Operator, assertion.Value)
+	if err != nil {
+		return fmt.Sprintf("json assertion failed: %s (%v)", expression, err)
+	}
+	if !ok {
+		return fmt.Sprintf("json assertion failed: %s (got %s)", expression, jsonString(actual))
+	}
+
+	return ""
+}
+
+// lookupJsonPath resolves a JSONPath-style expression made of dotted keys and
+// array indexes, e.g. $.checks[0].status or $["db"].status.
+func lookupJsonPath(document any, path string) (any, error) {
+	current := document
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			if key == "" {
+				return nil, fmt.Errorf("empty key in path %q", path)
+			}
+
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%q is not an object", key)
+			}
+			current, ok = object[key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated [ in path %q", path)
+			}
+			selector := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			if unquoted, err := strconv.Unquote(strings.ReplaceAll(selector, "'", "\"")); err == nil {
+				object, ok := current.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("%q is not an object", unquoted)
+				}
+				current, ok = object[unquoted]
+				if !ok {
+					return nil, fmt.Errorf("key %q not found", unquoted)
+				}
+				continue
+			}
+
+			index, err := strconv.Atoi(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q", selector)
+			}
+			array, ok := current.([]any)
+			if !ok {
+				return nil, fmt.Errorf("value at [%d] is not an array", index)
+			}
+			if index < 0 {
+				index += len(array)
+			}
+			if index < 0 || index >= len(array) {
+				return nil, fmt.Errorf("index %d out of range", index)
+			}
+			current = array[index]
+		default:
+			// Allow paths written without the leading "$." such as "status".
+			rest = "." + rest
+		}
+	}
+
+	return current, nil
+}
+
+func compareJsonValue(actual any, operator, expected string) (bool, error) {
+	switch operator {
+	case "exists":
+		return true, nil
+	case "eq":
+		return jsonEquals(actual, expected), nil
+	case "ne":
+		return !jsonEquals(actual, expected), nil
+	case "contains":
+		switch value := actual.(type) {
+		case string:
+			return strings.Contains(value, expected), nil
+		case []any:
+			for _, item := range value {
+				if jsonEquals(item, expected) {
+					return true, nil
+				}
+			}
+			return false, nil
+		default:
+			return false, fmt.Errorf("contains requires a string or an array")
+		}
+	case "gt", "gte", "lt", "lte":
+		number, ok := actual.(json.Number)
+		if !ok {
+			return false, fmt.Errorf("value %s is not a number", jsonString(actual))
+		}
+		left, err := number.Float64()
+		if err != nil {
+			return false, err
+		}
+		right, err := strconv.ParseFloat(expected, 64)
+		if err != nil {
+			return false, fmt.Errorf("expected value %q is not a number", expected)
+		}
+
+		switch operator {
+		case "gt":
+			return left > right, nil
+		case "gte":
+			return left >= right, nil
+		case "lt":
+			return left < right, nil
+		default:
+			return left <= right, nil
+		}
+	default:
+		return false, fmt.Errorf("unknown operator %q", operator)
+	}
+}
+
+func jsonEquals(actual any, expected string) bool {
+	if number, ok := actual.(json.Number); ok {
+		left, err := number.Float64()
+		right, parseErr := strconv.ParseFloat(expected, 64)
+		if err == nil && parseErr == nil {
+			return left == right
+		}
+	}
+
+	if value, ok := actual.(string); ok {
+		return value == expected
+	}
+
+	return jsonString(actual) == expected
+}
+
+func jsonString(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
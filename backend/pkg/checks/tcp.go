@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func init() {
+	Register("tcp", &TCP{})
+}
+
+// TCP reports a monitor as up when a connection to its host:port can be
+// established within the monitor's timeout.
+type TCP struct{}
+
+func (t *TCP) Check(ctx context.Context, monitor *models.Monitor) *Result {
+	address, err := tcpAddress(monitor.Url)
+	if err != nil {
+		return Down("error", fmt.Sprintf("invalid address: %v", err))
+	}
+
+	dialer := net.Dialer{Timeout: Timeout(monitor)}
+	startTime := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return Down("error", fmt.Sprintf("unable to connect: %v", err))
+	}
+	latency := time.Since(startTime).Milliseconds()
+	conn.Close()
+
+	return &Result{
+		Status:     "green",
+		StatusCode: "open",
+		Latency:    int(latency),
+		Message:    fmt.Sprintf("connected to %v", address),
+	}
+}
+
+// tcpAddress accepts either host:port or tcp://host:port.
+func tcpAddress(target string) (string, error) {
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", err
+		}
+		target = u.Host
+	}
+
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return "", err
+	}
+	if host == "" || port == "" {
+		return "", fmt.Errorf("%q must be in host:port form", target)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
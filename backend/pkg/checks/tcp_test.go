@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func TestTCPCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	open := listener.Addr().String()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refused := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		status     string
+		statusCode string
+	}{
+		{"open", open, "green", "open"},
+		{"open with scheme", "tcp://" + open, "green", "open"},
+		{"refused", refused, "red", "error"},
+		{"missing port", "127.0.0.1", "red", "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := new(TCP).Check(context.Background(), &models.Monitor{Url: tt.url, Timeout: 1})
+			if result.Status != tt.status || result.StatusCode != tt.statusCode {
+				t.Errorf("got %v %v (%v), want %v %v", result.Status, result.StatusCode, result.Message, tt.status, tt.statusCode)
+			}
+		})
+	}
+}
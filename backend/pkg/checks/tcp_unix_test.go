@@ -0,0 +1,62 @@
+//go:build unix
+
+package checks
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func TestTCPCheckTimeout(t *testing.T) {
+	address := fullListener(t)
+
+	start := time.Now()
+	result := new(TCP).Check(context.Background(), &models.Monitor{Url: address, Timeout: 1})
+	if result.Status != "red" || !strings.Contains(result.Message, "timeout") {
+		t.Fatalf("got %v (%v), want red with a timeout", result.Status, result.Message)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("check took %v, want it to give up after the 1s timeout", elapsed)
+	}
+}
+
+// fullListener returns the address of a listener whose accept queue is
+// full, so that new connections to it hang until they time out.
+func fullListener(t *testing.T) string {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { syscall.Close(fd) })
+
+	if err := syscall.Bind(fd, &syscall.SockaddrInet4{Addr: [4]byte{127, 0, 0, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Listen(fd, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	sa, err := syscall.Getsockname(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(sa.(*syscall.SockaddrInet4).Port))
+
+	for range 16 {
+		conn, err := net.DialTimeout("tcp", address, 100*time.Millisecond)
+		if err != nil {
+			return address
+		}
+		t.Cleanup(func() { conn.Close() })
+	}
+
+	t.Skip("accept queue of the listener never filled up")
+	return ""
+}
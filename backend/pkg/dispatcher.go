@@ -0,0 +1,247 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/alerts"
+)
+
+const (
+	dispatchInterval    = 5 * time.Second
+	dispatchBatchSize   = 50
+	deliveryTimeout     = 30 * time.Second
+	maxDeliveryAttempts = 10
+	baseRetryDelay      = 15 * time.Second
+	maxRetryDelay       = 30 * time.Minute
+)
+
+// permanentError marks a delivery failure that retrying cannot fix.
+type permanentError struct {
+	error
+}
+
+func (e *permanentError) Unwrap() error { return e.error }
+
+// Dispatcher delivers the alerts queued in the notification outbox,
+// retrying failed deliveries with exponential backoff and logging every
+// attempt.
+type Dispatcher struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wake          chan struct{}
+	stopWaitGroup sync.WaitGroup
+	db            DB
+}
+
+func NewDispatcher(db DB) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		ctx:    ctx,
+		cancel: cancel,
+		wake:   make(chan struct{}, 1),
+		db:     db,
+	}
+}
+
+func (d *Dispatcher) Start() {
+	d.stopWaitGroup.Add(1)
+	go d.run()
+}
+
+// Stop cancels the deliveries in flight, which stay queued for the next
+// start, and waits for the dispatcher to exit or for ctx to expire.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.stopWaitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wake makes the dispatcher look for due deliveries right away.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) run() {
+	defer d.stopWaitGroup.Done()
+
+	timer := time.NewTimer(dispatchInterval)
+	defer timer.Stop()
+
+	for {
+		d.dispatch()
+
+		timer.Reset(dispatchInterval)
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-d.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// dispatch delivers due entries until none are left. Every delivery either
+// succeeds, fails for good or is pushed into the future, so this ends; if
+// the outcome cannot be saved, the rest waits for the next round.
+func (d *Dispatcher) dispatch() {
+	for d.ctx.Err() == nil {
+		entries, err := d.db.DueNotifications(d.ctx, time.Now(), dispatchBatchSize)
+		if err != nil {
+			if d.ctx.Err() == nil {
+				log.Printf("Error when trying to retrieve due notifications: %v", err.Error())
+			}
+			return
+		}
+
+		if len(entries) == 0 {
+			return
+		}
+
+		for _, entry := range entries {
+			if d.ctx.Err() != nil {
+				return
+			}
+			// An entry that could not be updated is still due, so
+			// carrying on would send it again right away.
+			if err := d.deliver(entry); err != nil {
+				log.Printf("Error when trying to update notification outbox: %v", err.Error())
+				return
+			}
+		}
+	}
+}
+
+// deliver sends the entry and records the attempt. It returns an error
+// when the outbox could not be updated with the outcome.
+func (d *Dispatcher) deliver(entry *models.OutboxEntry) error {
+	response, err := d.send(entry)
+	if d.ctx.Err() != nil {
+		// Shutting down, the entry is delivered after the next start.
+		return nil
+	}
+
+	entry.Attempts++
+	delivery := &models.NotificationDelivery{
+		NotificationId: entry.NotificationId,
+		OutboxId:       entry.ID,
+		Attempt:        entry.Attempts,
+		CreatedAt:      time.Now(),
+	}
+	if response != nil {
+		delivery.StatusCode = response.StatusCode
+	}
+
+	if err == nil {
+		delivery.Delivered = true
+		entry.Status = "delivered"
+		entry.LastError = ""
+	} else {
+		delivery.Error = err.Error()
+		entry.LastError = err.Error()
+
+		if !retryable(err) || entry.Attempts >= maxDeliveryAttempts {
+			entry.Status = "failed"
+			log.Printf("Giving up on %v alert for notification channel %d after %d attempts: %v", entry.Type, entry.NotificationId, entry.Attempts, err)
+		} else {
+			entry.NextAttemptAt = time.Now().Add(retryDelay(entry.Attempts, err))
+			log.Printf("Delivery of %v alert to notification channel %d failed, retrying at %v: %v", entry.Type, entry.NotificationId, entry.NextAttemptAt.Format(time.RFC3339), err)
+		}
+	}
+
+	if err := d.db.SaveDelivery(d.ctx, delivery); err != nil {
+		log.Printf("Error when trying to save notification delivery: %v", err.Error())
+	}
+
+	return d.db.UpdateOutboxEntry(d.ctx, entry)
+}
+
+func (d *Dispatcher) send(entry *models.OutboxEntry) (*alerts.Response, error) {
+	alert := new(alerts.Alert)
+	if err := json.Unmarshal(entry.Payload, alert); err != nil {
+		return nil, &permanentError{fmt.Errorf("invalid alert payload: %w", err)}
+	}
+	alert.DeliveryId = entry.ID
+
+	return d.sendAlert(d.ctx, entry.Provider, entry.Data, alert)
+}
+
+// Test sends a sample alert to a notification channel right away, the
+// same way queued alerts are delivered. Providers that open an incident
+// for it have it resolved again, so that no one is paged for a test.
+func (d *Dispatcher) Test(ctx context.Context, provider string, data json.RawMessage) (*alerts.Response, error) {
+	alert := alerts.TestAlert()
+	response, err := d.sendAlert(ctx, provider, data, alert)
+	if err != nil || !alerts.Resolves(provider) {
+		return response, err
+	}
+
+	if _, err := d.sendAlert(ctx, provider, data, alerts.TestResolution(alert)); err != nil {
+		return response, fmt.Errorf("failed to resolve test alert: %w", err)
+	}
+
+	return response, nil
+}
+
+func (d *Dispatcher) sendAlert(ctx context.Context, provider string, data json.RawMessage, alert *alerts.Alert) (*alerts.Response, error) {
+	notifier, err := alerts.New(provider, data)
+	if err != nil {
+		return nil, &permanentError{err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	return notifier.Send(ctx, alert)
+}
+
+func retryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	var status *alerts.StatusError
+	if errors.As(err, &status) {
+		return status.Temporary()
+	}
+
+	return true
+}
+
+// retryDelay doubles the delay with every attempt, but waits at least as
+// long as the provider asked for with Retry-After.
+func retryDelay(attempt int, err error) time.Duration {
+	delay := maxRetryDelay
+	if attempt < 20 {
+		delay = min(baseRetryDelay<<(attempt-1), maxRetryDelay)
+	}
+
+	var status *alerts.StatusError
+	if errors.As(err, &status) && status.RetryAfter > delay {
+		delay = status.RetryAfter
+	}
+
+	return delay
+}
@@ -0,0 +1,269 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/alerts"
+)
+
+// fakeSend answers the deliveries to the "fake" provider.
+var fakeSend func(alert *alerts.Alert) (*alerts.Response, error)
+
+type fakeNotifier struct{}
+
+func (n *fakeNotifier) Send(ctx context.Context, alert *alerts.Alert) (*alerts.Response, error) {
+	return fakeSend(alert)
+}
+
+func init() {
+	alerts.Register("fake", func() alerts.Notifier { return new(fakeNotifier) })
+}
+
+// fakeOutbox keeps the notification outbox in memory. Like the repository,
+// it hands out due entries in order and only the oldest pending entry of
+// each notification channel.
+type fakeOutbox struct {
+	DB
+	entries     []*models.OutboxEntry
+	deliveries  []*models.NotificationDelivery
+	failUpdates bool
+}
+
+func (o *fakeOutbox) DueNotifications(ctx context.Context, now time.Time, limit int) ([]*models.OutboxEntry, error) {
+	var due []*models.OutboxEntry
+	waiting := make(map[int]bool)
+	for _, entry := range o.entries {
+		if entry.Status != "pending" || waiting[entry.NotificationId] {
+			continue
+		}
+		waiting[entry.NotificationId] = true
+
+		if !entry.NextAttemptAt.After(now) && len(due) < limit {
+			copied := *entry
+			due = append(due, &copied)
+		}
+	}
+
+	return due, nil
+}
+
+func (o *fakeOutbox) UpdateOutboxEntry(ctx context.Context, entry *models.OutboxEntry) error {
+	if o.failUpdates {
+		return errors.New("database is locked")
+	}
+
+	for i := range o.entries {
+		if o.entries[i].ID == entry.ID {
+			copied := *entry
+			o.entries[i] = &copied
+		}
+	}
+
+	return nil
+}
+
+func (o *fakeOutbox) SaveDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	o.deliveries = append(o.deliveries, delivery)
+	return nil
+}
+
+func (o *fakeOutbox) entry(id int) *models.OutboxEntry {
+	for _, entry := range o.entries {
+		if entry.ID == id {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+func outboxEntry(t *testing.T, id, notificationId int) *models.OutboxEntry {
+	t.Helper()
+
+	payload, err := json.Marshal(alerts.TestAlert())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return &models.OutboxEntry{
+		ID:             id,
+		NotificationId: notificationId,
+		Provider:       "fake",
+		Type:           "DOWN",
+		Payload:        payload,
+		Status:         "pending",
+		NextAttemptAt:  time.Now().Add(-time.Second),
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tooManyRequests := func(retryAfter time.Duration) error {
+		return &alerts.StatusError{Response: alerts.Response{StatusCode: http.StatusTooManyRequests}, RetryAfter: retryAfter}
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{"first retry", 1, errors.New("connection refused"), 15 * time.Second},
+		{"doubles", 2, errors.New("connection refused"), 30 * time.Second},
+		{"doubles again", 4, errors.New("connection refused"), 2 * time.Minute},
+		{"capped", 8, errors.New("connection refused"), 30 * time.Minute},
+		{"capped without overflowing", 64, errors.New("connection refused"), 30 * time.Minute},
+		{"longer Retry-After", 1, tooManyRequests(10 * time.Minute), 10 * time.Minute},
+		{"Retry-After past the cap", 9, tooManyRequests(time.Hour), time.Hour},
+		{"shorter Retry-After", 3, tooManyRequests(5 * time.Second), time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.attempt, tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	statusError := func(statusCode int, retryAfter time.Duration) error {
+		return &alerts.StatusError{Response: alerts.Response{StatusCode: statusCode}, RetryAfter: retryAfter}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		attempts int
+		provider string
+		payload  string
+		status   string
+		// retryIn is the delay before the next attempt of a pending entry.
+		retryIn time.Duration
+	}{
+		{name: "delivered", status: "delivered"},
+		{name: "server error", err: statusError(http.StatusBadGateway, 0), status: "pending", retryIn: 15 * time.Second},
+		{name: "network error", err: errors.New("connection reset"), attempts: 2, status: "pending", retryIn: time.Minute},
+		{name: "rate limited", err: statusError(http.StatusTooManyRequests, 10*time.Minute), status: "pending", retryIn: 10 * time.Minute},
+		{name: "client error", err: statusError(http.StatusUnauthorized, 0), status: "failed"},
+		{name: "last attempt", err: statusError(http.StatusServiceUnavailable, 0), attempts: maxDeliveryAttempts - 1, status: "failed"},
+		{name: "unknown provider", provider: "carrier pigeon", status: "failed"},
+		{name: "invalid payload", payload: "{", status: "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := outboxEntry(t, 7, 1)
+			entry.Attempts = tt.attempts
+			if tt.provider != "" {
+				entry.Provider = tt.provider
+			}
+			if tt.payload != "" {
+				entry.Payload = json.RawMessage(tt.payload)
+			}
+			outbox := &fakeOutbox{entries: []*models.OutboxEntry{entry}}
+
+			var sent []*alerts.Alert
+			fakeSend = func(alert *alerts.Alert) (*alerts.Response, error) {
+				sent = append(sent, alert)
+				return &alerts.Response{StatusCode: http.StatusOK}, tt.err
+			}
+
+			NewDispatcher(outbox).dispatch()
+
+			if tt.provider == "" && tt.payload == "" && (len(sent) != 1 || sent[0].DeliveryId != 7) {
+				t.Fatalf("got %d alerts sent, want one with delivery id 7", len(sent))
+			}
+
+			got := outbox.entry(7)
+			if got.Status != tt.status || got.Attempts != tt.attempts+1 {
+				t.Errorf("got %v after %d attempts, want %v after %d", got.Status, got.Attempts, tt.status, tt.attempts+1)
+			}
+			if tt.status == "pending" {
+				if retryIn := time.Until(got.NextAttemptAt); retryIn > tt.retryIn || retryIn < tt.retryIn-time.Minute/2 {
+					t.Errorf("got retry in %v, want %v", retryIn, tt.retryIn)
+				}
+			}
+			if (tt.status == "delivered") != (got.LastError == "") {
+				t.Errorf("got last error %q", got.LastError)
+			}
+
+			if len(outbox.deliveries) != 1 {
+				t.Fatalf("got %d deliveries logged, want 1", len(outbox.deliveries))
+			}
+			if delivery := outbox.deliveries[0]; delivery.OutboxId != 7 || delivery.Attempt != tt.attempts+1 || delivery.Delivered != (tt.status == "delivered") {
+				t.Errorf("got delivery %+v", delivery)
+			}
+		})
+	}
+}
+
+func TestDispatcherChannelOrder(t *testing.T) {
+	outbox := &fakeOutbox{entries: []*models.OutboxEntry{
+		outboxEntry(t, 1, 1),
+		outboxEntry(t, 2, 1),
+		outboxEntry(t, 3, 2),
+	}}
+
+	var sent []int
+	down := true
+	fakeSend = func(alert *alerts.Alert) (*alerts.Response, error) {
+		sent = append(sent, alert.DeliveryId)
+		if down && alert.DeliveryId == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return &alerts.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	dispatcher := NewDispatcher(outbox)
+	dispatcher.dispatch()
+
+	// The second alert of channel 1 waits for the retry of the first, the
+	// alert of channel 2 does not.
+	if !slices.Equal(sent, []int{1, 3}) {
+		t.Errorf("got %v sent, want [1 3]", sent)
+	}
+	if entry := outbox.entry(2); entry.Status != "pending" || entry.Attempts != 0 {
+		t.Errorf("got second entry %v after %d attempts", entry.Status, entry.Attempts)
+	}
+
+	sent, down = nil, false
+	outbox.entry(1).NextAttemptAt = time.Now()
+	dispatcher.dispatch()
+
+	if !slices.Equal(sent, []int{1, 2}) {
+		t.Errorf("got %v sent, want [1 2]", sent)
+	}
+	for _, entry := range outbox.entries {
+		if entry.Status != "delivered" {
+			t.Errorf("got entry %d %v", entry.ID, entry.Status)
+		}
+	}
+}
+
+func TestDispatcherStopsWhenUpdateFails(t *testing.T) {
+	outbox := &fakeOutbox{
+		entries:     []*models.OutboxEntry{outboxEntry(t, 1, 1), outboxEntry(t, 2, 2)},
+		failUpdates: true,
+	}
+
+	var sent []int
+	fakeSend = func(alert *alerts.Alert) (*alerts.Response, error) {
+		sent = append(sent, alert.DeliveryId)
+		return &alerts.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	NewDispatcher(outbox).dispatch()
+
+	// The first entry is still due, so going on would send it again.
+	if !slices.Equal(sent, []int{1}) {
+		t.Errorf("got %v sent, want [1]", sent)
+	}
+}
@@ -0,0 +1,174 @@
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	// 7 is accepted for Sunday as well.
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// cronSchedule is a standard five field cron expression: minute, hour,
+// day of month, month and day of week. Every field is a set of bits.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// As in cron, when both days are restricted a day matching either
+	// one is enough.
+	domAny, dowAny bool
+	location       *time.Location
+}
+
+func parseCron(expr string, location *time.Location) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression must have %d fields, got %d", len(cronFields), len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		b, err := parseCronField(strings.ToUpper(field), cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+
+	dow := bits[4]
+	if dow&(1<<7) != 0 {
+		dow = dow&^(1<<7) | 1
+	}
+
+	return &cronSchedule{
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      dow,
+		domAny:   strings.HasPrefix(fields[2], "*"),
+		dowAny:   strings.HasPrefix(fields[4], "*"),
+		location: location,
+	}, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and
+// steps like "1,5", "9-17" or "*/15".
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		values, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return 0, fmt.Errorf("invalid step in %v field: %q", f.name, part)
+			}
+			values, step = part[:i], s
+		}
+
+		low, high := f.min, f.max
+		if values != "*" {
+			var err error
+			if i := strings.Index(values, "-"); i >= 0 {
+				if low, err = f.value(values[:i]); err != nil {
+					return 0, err
+				}
+				if high, err = f.value(values[i+1:]); err != nil {
+					return 0, err
+				}
+			} else {
+				if low, err = f.value(values); err != nil {
+					return 0, err
+				}
+				// "5/15" means every 15 starting at 5.
+				if step == 1 {
+					high = low
+				}
+			}
+		}
+
+		if low > high {
+			return 0, fmt.Errorf("invalid range in %v field: %q", f.name, part)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	v, ok := f.names[s]
+	if !ok {
+		var err error
+		if v, err = strconv.Atoi(s); err != nil {
+			return 0, fmt.Errorf("invalid value in %v field: %q", f.name, s)
+		}
+	}
+
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%v must be between %d and %d, got %d", f.name, f.min, f.max, v)
+	}
+
+	return v, nil
+}
+
+// Next returns the first time after the given one that matches the
+// expression, or the zero time if there is none within five years.
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		var next time.Time
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+		case !s.dayMatches(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+		// A local time skipped when DST starts may be normalized to
+		// the hour before it, which must not send the search back.
+		if !next.After(t) {
+			next = next.Add(time.Hour)
+		}
+		t = next
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
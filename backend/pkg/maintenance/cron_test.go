@@ -0,0 +1,165 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %v is not available: %v", name, err)
+	}
+
+	return location
+}
+
+// nextTimes returns the next n times of the schedule after the given one.
+func nextTimes(schedule Schedule, after time.Time, n int) []time.Time {
+	var times []time.Time
+	for range n {
+		after = schedule.Next(after)
+		times = append(times, after)
+		if after.IsZero() {
+			break
+		}
+	}
+
+	return times
+}
+
+func TestCronNext(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+	kathmandu := mustLoadLocation(t, "Asia/Kathmandu")
+
+	tests := []struct {
+		name     string
+		expr     string
+		location *time.Location
+		after    time.Time
+		want     []time.Time
+	}{
+		{
+			name: "every 15 minutes in working hours", expr: "*/15 9-17 * * MON-FRI", location: time.UTC,
+			after: time.Date(2026, 10, 16, 17, 50, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 19, 9, 15, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "exact minute is not repeated", expr: "30 2 * * *", location: time.UTC,
+			after: time.Date(2026, 10, 18, 2, 30, 0, 0, time.UTC),
+			want:  []time.Time{time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC)},
+		},
+		{
+			name: "day of month or day of week", expr: "0 0 13 * FRI", location: time.UTC,
+			after: time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 11, 13, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 4, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "7 is Sunday", expr: "0 4 * * 7", location: time.UTC,
+			after: time.Date(2026, 10, 18, 5, 0, 0, 0, time.UTC),
+			want:  []time.Time{time.Date(2026, 10, 25, 4, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "named months", expr: "0 0 1 JAN,JUL *", location: time.UTC,
+			after: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "leap day", expr: "0 0 29 2 *", location: time.UTC,
+			after: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			want:  []time.Time{time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "half hour time zone", expr: "0 11 * * *", location: kolkata,
+			after: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 10, 18, 5, 30, 0, 0, time.UTC),
+				time.Date(2026, 10, 19, 5, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "quarter hour time zone", expr: "0 * * * *", location: kathmandu,
+			after: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 10, 18, 0, 15, 0, 0, time.UTC),
+				time.Date(2026, 10, 18, 1, 15, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "local time across the start of DST", expr: "0 3 * * SUN", location: newYork,
+			after: time.Date(2026, 3, 1, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "skipped hour at the start of DST", expr: "30 2 * * *", location: newYork,
+			after: time.Date(2026, 3, 7, 12, 0, 0, 0, newYork),
+			want:  []time.Time{time.Date(2026, 3, 9, 2, 30, 0, 0, newYork)},
+		},
+		{
+			name: "local time across the end of DST", expr: "0 9 * * *", location: newYork,
+			after: time.Date(2026, 10, 31, 12, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "impossible date", expr: "0 0 31 2 *", location: time.UTC,
+			after: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			want:  []time.Time{{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.expr, tt.location)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := nextTimes(schedule, tt.after, len(tt.want))
+			for i := range tt.want {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d: got %v, want %v", i+1, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"17-9 * * * *",
+		"* * * FOO *",
+		"a * * * *",
+	} {
+		if _, err := parseCron(expr, time.UTC); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+}
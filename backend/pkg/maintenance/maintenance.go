@@ -0,0 +1,130 @@
+// Package maintenance works out when maintenance windows are in progress.
+package maintenance
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+// Schedule tells when the occurrences of a recurring window start.
+type Schedule interface {
+	// Next returns the first start after the given time, or the zero
+	// time if there is none.
+	Next(after time.Time) time.Time
+}
+
+// ValidateRule checks the rule of a window with the given recurrence.
+func ValidateRule(recurrence, rule string) error {
+	_, err := parse(recurrence, rule, time.Now().UTC())
+	return err
+}
+
+func parse(recurrence, rule string, start time.Time) (Schedule, error) {
+	switch recurrence {
+	case "":
+		return nil, nil
+	case "cron":
+		return parseCron(rule, start.Location())
+	case "rrule":
+		r, err := parseRRule(rule, start.Location())
+		if err != nil {
+			return nil, err
+		}
+		return &rruleSchedule{rule: r, start: start}, nil
+	}
+
+	return nil, fmt.Errorf("unknown recurrence %q", recurrence)
+}
+
+// Occurrence returns the occurrence of the window in progress at t or,
+// if there is none, the next one. ok is false when there are no more.
+func Occurrence(window *models.MaintenanceWindow, t time.Time) (start, end time.Time, ok bool) {
+	if window.Recurrence == "" {
+		if window.EndsAt == nil || !window.EndsAt.After(t) {
+			return time.Time{}, time.Time{}, false
+		}
+		return window.StartsAt, *window.EndsAt, true
+	}
+
+	location := time.UTC
+	if window.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(window.Timezone); err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	schedule, err := parse(window.Recurrence, window.Rule, window.StartsAt.In(location))
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	// The occurrence in progress at t started within the last Duration.
+	duration := time.Duration(window.Duration) * time.Minute
+	after := t.Add(-duration)
+	if first := window.StartsAt.Add(-time.Nanosecond); after.Before(first) {
+		after = first
+	}
+
+	start = schedule.Next(after)
+	if start.IsZero() || (window.EndsAt != nil && !start.Before(*window.EndsAt)) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return start, start.Add(duration), true
+}
+
+// InProgress reports whether an occurrence of the window is in progress
+// at t.
+func InProgress(window *models.MaintenanceWindow, t time.Time) bool {
+	start, _, ok := Occurrence(window, t)
+	return ok && !start.After(t)
+}
+
+// Calendar finds the window in progress among a set of windows. It keeps
+// the occurrence of every window until it is over, so that the rules are
+// evaluated once per occurrence rather than on every lookup.
+type Calendar struct {
+	mutex   sync.Mutex
+	entries []calendarEntry
+}
+
+type calendarEntry struct {
+	window *models.MaintenanceWindow
+	// The occurrence found at from is the one in progress or the next
+	// one until it ends, ok is false when there are no more.
+	from, start, end time.Time
+	ok               bool
+}
+
+func NewCalendar(windows []*models.MaintenanceWindow) *Calendar {
+	entries := make([]calendarEntry, len(windows))
+	for i, window := range windows {
+		entries[i].window = window
+	}
+
+	return &Calendar{entries: entries}
+}
+
+// Active returns the first of the windows in progress at t, if any.
+func (c *Calendar) Active(t time.Time) *models.MaintenanceWindow {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i := range c.entries {
+		entry := &c.entries[i]
+		if entry.from.IsZero() || t.Before(entry.from) || (entry.ok && !t.Before(entry.end)) {
+			entry.start, entry.end, entry.ok = Occurrence(entry.window, t)
+			entry.from = t
+		}
+
+		if entry.ok && !entry.start.After(t) {
+			return entry.window
+		}
+	}
+
+	return nil
+}
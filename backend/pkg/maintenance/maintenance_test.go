@@ -0,0 +1,86 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+func TestOccurrence(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	oneOff := &models.MaintenanceWindow{StartsAt: at(18, 10, 0), EndsAt: ptr(at(18, 11, 0))}
+	daily := &models.MaintenanceWindow{StartsAt: at(10, 10, 0), Recurrence: "cron", Rule: "0 10 * * *", Duration: 60}
+	ending := &models.MaintenanceWindow{StartsAt: at(10, 10, 0), EndsAt: ptr(at(12, 10, 0)), Recurrence: "rrule", Rule: "FREQ=DAILY", Duration: 60}
+	kolkata := &models.MaintenanceWindow{StartsAt: at(10, 5, 30), Recurrence: "cron", Rule: "0 11 * * *", Duration: 30, Timezone: "Asia/Kolkata"}
+	unknownZone := &models.MaintenanceWindow{StartsAt: at(10, 10, 0), Recurrence: "cron", Rule: "0 10 * * *", Duration: 60, Timezone: "Mars/Olympus"}
+
+	tests := []struct {
+		name       string
+		window     *models.MaintenanceWindow
+		t          time.Time
+		start      time.Time
+		ok         bool
+		inProgress bool
+	}{
+		{"one-off before it starts", oneOff, at(18, 9, 59), at(18, 10, 0), true, false},
+		{"one-off as it starts", oneOff, at(18, 10, 0), at(18, 10, 0), true, true},
+		{"one-off right before it ends", oneOff, at(18, 11, 0).Add(-time.Second), at(18, 10, 0), true, true},
+		{"one-off as it ends", oneOff, at(18, 11, 0), time.Time{}, false, false},
+		{"recurring before the first occurrence", daily, at(9, 10, 30), at(10, 10, 0), true, false},
+		{"recurring as an occurrence starts", daily, at(18, 10, 0), at(18, 10, 0), true, true},
+		{"recurring during an occurrence", daily, at(18, 10, 59), at(18, 10, 0), true, true},
+		{"recurring as an occurrence ends", daily, at(18, 11, 0), at(19, 10, 0), true, false},
+		{"recurring during the last occurrence", ending, at(11, 10, 30), at(11, 10, 0), true, true},
+		{"recurring with an occurrence starting at its end", ending, at(11, 11, 0), time.Time{}, false, false},
+		{"time zone", kolkata, at(18, 5, 45), at(18, 5, 30), true, true},
+		{"unknown time zone", unknownZone, at(18, 10, 30), time.Time{}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := Occurrence(tt.window, tt.t)
+			if ok != tt.ok || !start.Equal(tt.start) {
+				t.Errorf("got %v %v, want %v %v", start, ok, tt.start, tt.ok)
+			}
+			if ok && tt.window.Recurrence != "" && end.Sub(start) != time.Duration(tt.window.Duration)*time.Minute {
+				t.Errorf("got end %v for start %v", end, start)
+			}
+
+			if got := InProgress(tt.window, tt.t); got != tt.inProgress {
+				t.Errorf("got in progress %v, want %v", got, tt.inProgress)
+			}
+		})
+	}
+}
+
+func TestCalendarActive(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 30, 0, 0, time.UTC)
+	ended := &models.MaintenanceWindow{ID: 1, StartsAt: now.Add(-2 * time.Hour), EndsAt: &now}
+	daily := &models.MaintenanceWindow{ID: 2, StartsAt: now.AddDate(0, 0, -1), Recurrence: "rrule", Rule: "FREQ=DAILY", Duration: 60}
+	calendar := NewCalendar([]*models.MaintenanceWindow{ended, daily})
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want *models.MaintenanceWindow
+	}{
+		{"first of the windows in progress", now.Add(-time.Minute), ended},
+		{"as the first window ends", now, daily},
+		{"as the occurrence ends", now.Add(time.Hour), nil},
+		{"before the next occurrence", now.Add(23*time.Hour + 59*time.Minute), nil},
+		{"during the next occurrence", now.Add(24 * time.Hour), daily},
+		{"back in time", now.Add(-90 * time.Minute), ended},
+		{"before the windows", now.AddDate(0, 0, -2), nil},
+	}
+
+	for _, tt := range tests {
+		if got := calendar.Active(tt.t); got != tt.want {
+			t.Errorf("%v: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
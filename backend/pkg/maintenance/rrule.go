@@ -0,0 +1,253 @@
+package maintenance
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRRuleDays bounds how far ahead occurrences of a rule are looked for.
+const maxRRuleDays = 10 * 366
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+type rruleWeekday struct {
+	weekday time.Weekday
+	// n is the nth weekday of the month, counting from the end when
+	// negative and any of them when 0.
+	n int
+}
+
+// rrule is the subset of RFC 5545 recurrence rules that maintenance
+// windows support: daily, weekly and monthly frequencies with INTERVAL,
+// BYDAY, BYMONTHDAY, COUNT and UNTIL. Occurrences start at the time of
+// day of the first one.
+type rrule struct {
+	freq       string
+	interval   int
+	byDay      []rruleWeekday
+	byMonthDay []int
+	count      int
+	until      time.Time
+}
+
+// parseRRule parses the rule, an UNTIL without a UTC offset is in the
+// given location.
+func parseRRule(rule string, location *time.Location) (*rrule, error) {
+	r := &rrule{interval: 1}
+
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		var err error
+		switch key {
+		case "FREQ":
+			if !slices.Contains([]string{"DAILY", "WEEKLY", "MONTHLY"}, value) {
+				return nil, fmt.Errorf("unsupported frequency %q", value)
+			}
+			r.freq = value
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval < 1 {
+				return nil, fmt.Errorf("invalid interval %q", value)
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err != nil || r.count < 1 {
+				return nil, fmt.Errorf("invalid count %q", value)
+			}
+		case "UNTIL":
+			r.until, err = parseRRuleTime(value, location)
+			if err != nil {
+				return nil, err
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := rruleWeekdays[day[max(len(day)-2, 0):]]
+				if !ok {
+					return nil, fmt.Errorf("invalid day %q", day)
+				}
+
+				n := 0
+				if prefix := day[:len(day)-2]; prefix != "" {
+					n, err = strconv.Atoi(prefix)
+					if err != nil || n == 0 || n < -5 || n > 5 {
+						return nil, fmt.Errorf("invalid day %q", day)
+					}
+				}
+				r.byDay = append(r.byDay, rruleWeekday{weekday: weekday, n: n})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid day of month %q", day)
+				}
+				r.byMonthDay = append(r.byMonthDay, n)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if r.freq == "" {
+		return nil, fmt.Errorf("rule must have a FREQ")
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, fmt.Errorf("rule can't have both COUNT and UNTIL")
+	}
+	for _, day := range r.byDay {
+		if day.n != 0 && r.freq != "MONTHLY" {
+			return nil, fmt.Errorf("numbered days are only supported with FREQ=MONTHLY")
+		}
+	}
+
+	return r, nil
+}
+
+func parseRRuleTime(value string, location *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid until %q", value)
+}
+
+type rruleSchedule struct {
+	rule  *rrule
+	start time.Time
+}
+
+// Next returns the first occurrence after the given time, or the zero time
+// if the rule has none left.
+func (s *rruleSchedule) Next(after time.Time) time.Time {
+	location := s.start.Location()
+	day := date(s.start)
+	// Without a COUNT the occurrences before after don't matter, so the
+	// search can start right before it.
+	if s.rule.count == 0 && after.After(s.start) {
+		day = date(after.In(location)).AddDate(0, 0, -1)
+	}
+
+	occurrences := 0
+	for range maxRRuleDays {
+		if s.rule.matches(day, s.start) {
+			occurrence := time.Date(day.Year(), day.Month(), day.Day(), s.start.Hour(), s.start.Minute(), s.start.Second(), 0, location)
+			if !occurrence.Before(s.start) {
+				if !s.rule.until.IsZero() && occurrence.After(s.rule.until) {
+					return time.Time{}
+				}
+
+				occurrences++
+				if s.rule.count > 0 && occurrences > s.rule.count {
+					return time.Time{}
+				}
+
+				if occurrence.After(after) {
+					return occurrence
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return time.Time{}
+}
+
+func (r *rrule) matches(day, start time.Time) bool {
+	first := date(start)
+
+	switch r.freq {
+	case "DAILY":
+		if days(first, day)%r.interval != 0 {
+			return false
+		}
+		return (len(r.byDay) == 0 || r.matchesWeekday(day)) && (len(r.byMonthDay) == 0 || r.matchesMonthDay(day))
+	case "WEEKLY":
+		weeks := days(weekStart(first), weekStart(day)) / 7
+		if weeks%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return r.matchesWeekday(day)
+	case "MONTHLY":
+		months := (day.Year()-first.Year())*12 + int(day.Month()) - int(first.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) > 0 {
+			return r.matchesWeekday(day) && (len(r.byMonthDay) == 0 || r.matchesMonthDay(day))
+		}
+		if len(r.byMonthDay) == 0 {
+			return day.Day() == start.Day()
+		}
+		return r.matchesMonthDay(day)
+	}
+
+	return false
+}
+
+func (r *rrule) matchesWeekday(day time.Time) bool {
+	for _, d := range r.byDay {
+		if d.weekday != day.Weekday() {
+			continue
+		}
+
+		switch {
+		case d.n == 0:
+			return true
+		case d.n > 0 && (day.Day()-1)/7+1 == d.n:
+			return true
+		case d.n < 0 && (daysInMonth(day)-day.Day())/7+1 == -d.n:
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *rrule) matchesMonthDay(day time.Time) bool {
+	for _, n := range r.byMonthDay {
+		if n == day.Day() || (n < 0 && daysInMonth(day)+n+1 == day.Day()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// date returns midnight of the day of t in its location.
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// days counts the calendar days between two dates, ignoring DST changes.
+func days(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a) / (24 * time.Hour))
+}
+
+// weekStart returns the Monday of the week of the date.
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func daysInMonth(day time.Time) int {
+	return time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
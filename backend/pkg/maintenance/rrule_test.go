@@ -0,0 +1,186 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRRuleNext(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			name: "daily", rule: "FREQ=DAILY", start: start,
+			after: start.AddDate(0, 0, 3),
+			want: []time.Time{
+				time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "first occurrence is the start", rule: "RRULE:FREQ=DAILY;INTERVAL=2", start: start,
+			after: start.Add(-time.Nanosecond),
+			want: []time.Time{
+				time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "every other week on days", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", start: start,
+			after: start,
+			want: []time.Time{
+				time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 14, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 26, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "weekly on the day of the start", rule: "FREQ=WEEKLY", start: start,
+			after: start,
+			want:  []time.Time{time.Date(2026, 1, 8, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "second Monday", rule: "FREQ=MONTHLY;BYDAY=2MO", start: start,
+			after: start,
+			want: []time.Time{
+				time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last Friday", rule: "FREQ=MONTHLY;BYDAY=-1FR", start: start,
+			after: start,
+			want: []time.Time{
+				time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 27, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "fifth Thursday only in months that have one", rule: "FREQ=MONTHLY;BYDAY=5TH", start: start,
+			after: start,
+			want: []time.Time{
+				time.Date(2026, 1, 29, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 4, 30, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last day of the month", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", start: start,
+			after: start,
+			want: []time.Time{
+				time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Friday the 13th", rule: "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", start: start,
+			after: start,
+			want: []time.Time{
+				time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 13, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "count", rule: "FREQ=DAILY;COUNT=3", start: start,
+			after: start.Add(-time.Nanosecond),
+			want: []time.Time{
+				time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name: "count is over long after the start", rule: "FREQ=WEEKLY;COUNT=2", start: start,
+			after: start.AddDate(5, 0, 0),
+			want:  []time.Time{{}},
+		},
+		{
+			name: "until in UTC", rule: "FREQ=DAILY;UNTIL=20260103T090000Z", start: start,
+			after: start,
+			want: []time.Time{
+				time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name: "until as a local date", rule: "FREQ=DAILY;UNTIL=20260103", start: time.Date(2026, 1, 1, 9, 0, 0, 0, newYork),
+			after: time.Date(2026, 1, 1, 9, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2026, 1, 2, 9, 0, 0, 0, newYork),
+				{},
+			},
+		},
+		{
+			name: "half hour time zone", rule: "FREQ=DAILY", start: time.Date(2026, 10, 1, 11, 0, 0, 0, kolkata),
+			after: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			want:  []time.Time{time.Date(2026, 10, 18, 5, 30, 0, 0, time.UTC)},
+		},
+		{
+			name: "local time across the start of DST", rule: "FREQ=DAILY", start: time.Date(2026, 3, 1, 9, 0, 0, 0, newYork),
+			after: time.Date(2026, 3, 7, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2026, 3, 7, 14, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "weeks across the end of DST", rule: "FREQ=WEEKLY;BYDAY=SU", start: time.Date(2026, 10, 25, 9, 0, 0, 0, newYork),
+			after: time.Date(2026, 10, 25, 9, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 8, 14, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parse("rrule", tt.rule, tt.start)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := nextTimes(schedule, tt.after, len(tt.want))
+			for i := range tt.want {
+				if i >= len(got) || !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d: got %v, want %v", i+1, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		if _, err := parseRRule(rule, time.UTC); err == nil {
+			t.Errorf("%q: expected an error", rule)
+		}
+	}
+}
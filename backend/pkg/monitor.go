@@ -1,197 +1,576 @@
 package pkg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
-	"strings"
+	"math/rand/v2"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/chamanbravo/upstat/internal/dto"
 	"github.com/chamanbravo/upstat/internal/models"
 	"github.com/chamanbravo/upstat/pkg/alerts"
+	"github.com/chamanbravo/upstat/pkg/checks"
+	"github.com/chamanbravo/upstat/pkg/maintenance"
+	"github.com/chamanbravo/upstat/svcerr"
 )
 
 type DB interface {
-	SaveIncident(incident *dto.SaveIncident) error
-	LatestIncidentByMonitorId(id int) (*models.Incident, error)
+	SaveIncident(ctx context.Context, incident *dto.SaveIncident) (*models.Incident, error)
+	OpenIncidentByMonitorId(ctx context.Context, id int) (*models.Incident, error)
+	ResolveIncident(ctx context.Context, incident *models.Incident, resolvedAt time.Time) error
 
-	RetrieveMonitors() ([]*models.Monitor, error)
-	FindMonitorById(id int) (*models.Monitor, error)
-	UpdateMonitorStatus(id int, status string) error
+	RetrieveMonitors(ctx context.Context) ([]*models.Monitor, error)
+	FindMonitorById(ctx context.Context, id int) (*models.Monitor, error)
+	UpdateMonitorStatus(ctx context.Context, id int, status string) error
+	ResumeMonitor(ctx context.Context, id int) error
 
-	SaveHeartbeat(heartbeat *models.Heartbeat) error
+	SaveHeartbeat(ctx context.Context, heartbeat *models.Heartbeat) error
 
-	FindNotificationChannelsByMonitorId(id int) ([]models.Notification, error)
+	MaintenanceWindowsByMonitorId(ctx context.Context, id int) ([]*models.MaintenanceWindow, error)
+
+	FindNotificationChannelsByMonitorId(ctx context.Context, id int) ([]models.Notification, error)
+
+	EnqueueNotification(ctx context.Context, entry *models.OutboxEntry) error
+	ReminderStates(ctx context.Context, monitorId, incidentId int) ([]*models.ReminderState, error)
+	DueNotifications(ctx context.Context, now time.Time, limit int) ([]*models.OutboxEntry, error)
+	UpdateOutboxEntry(ctx context.Context, entry *models.OutboxEntry) error
+	SaveDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
 }
 
 type Monitor struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
 	stopChannel   chan struct{}
+	stopOnce      sync.Once
 	stopWaitGroup sync.WaitGroup
-	goroutines    map[int]chan struct{}
+	jobs          map[int]*job
+	queue         jobQueue
+	wake          chan struct{}
+	work          chan *job
+	workers       int
+	resumeTimers  map[int]*time.Timer
 	mutex         sync.Mutex
 	db            DB
+	dispatcher    *Dispatcher
+	// calendars caches the maintenance windows of monitors, its version
+	// counts the invalidations so that a load racing one is not kept.
+	calendars        map[int]*maintenance.Calendar
+	calendarsVersion int
 }
 
-func New(db DB) *Monitor {
+func New(db DB, dispatcher *Dispatcher) *Monitor {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Monitor{
-		goroutines: make(map[int]chan struct{}, 0),
-		mutex:      sync.Mutex{},
-		db:         db,
+		ctx:          ctx,
+		cancel:       cancel,
+		stopChannel:  make(chan struct{}),
+		jobs:         make(map[int]*job, 0),
+		queue:        make(jobQueue, 0),
+		wake:         make(chan struct{}, 1),
+		work:         make(chan *job),
+		workers:      checkWorkers(),
+		resumeTimers: make(map[int]*time.Timer, 0),
+		mutex:        sync.Mutex{},
+		db:           db,
+		dispatcher:   dispatcher,
+		calendars:    make(map[int]*maintenance.Calendar),
+	}
+}
+
+// Schedule adds the monitor to the scheduler so that it is checked right
+// away, after a short random delay that keeps monitors added together
+// apart. If the monitor is already scheduled its configuration is replaced:
+// a new URL is checked right away, a new frequency restarts the schedule
+// and any other change takes effect on the next run.
+func (m *Monitor) Schedule(monitor *models.Monitor) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.cancelResume(monitor.ID)
+
+	interval := time.Duration(monitor.Frequency) * time.Second
+	next := time.Now().Add(jitter(interval).Abs())
+	if j, exists := m.jobs[monitor.ID]; exists {
+		switch previous := j.monitor; {
+		case previous.Url != monitor.Url || previous.Type != monitor.Type:
+		case previous.Frequency != monitor.Frequency:
+			next = next.Add(interval)
+		default:
+			j.monitor = monitor
+			return
+		}
+		m.remove(j)
 	}
+
+	m.push(&job{monitor: monitor, next: next})
+}
+
+// Unschedule removes the monitor from the scheduler. A check that is
+// already running is allowed to finish but is not scheduled again.
+func (m *Monitor) Unschedule(id int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.cancelResume(id)
+	delete(m.calendars, id)
+	if j, exists := m.jobs[id]; exists {
+		m.remove(j)
+		m.wakeUp()
+	}
+}
+
+// InvalidateMaintenance drops the cached maintenance windows, so that
+// changes to the windows or to the status pages of monitors apply to the
+// next checks.
+func (m *Monitor) InvalidateMaintenance() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calendars = make(map[int]*maintenance.Calendar)
+	m.calendarsVersion++
 }
 
-func (m *Monitor) StartGoroutine(monitor *models.Monitor) {
+// maintenanceCalendar returns the maintenance windows of the monitor,
+// loading them if they are not cached.
+func (m *Monitor) maintenanceCalendar(ctx context.Context, id int) (*maintenance.Calendar, error) {
+	m.mutex.Lock()
+	calendar, exists := m.calendars[id]
+	version := m.calendarsVersion
+	m.mutex.Unlock()
+	if exists {
+		return calendar, nil
+	}
+
+	windows, err := m.db.MaintenanceWindowsByMonitorId(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	calendar = maintenance.NewCalendar(windows)
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
+	if version == m.calendarsVersion {
+		m.calendars[id] = calendar
+	}
+
+	return calendar, nil
+}
+
+// CheckNow runs an out-of-band check of the monitor and returns the saved
+// heartbeat. It leaves the schedule and the monitor status untouched.
+func (m *Monitor) CheckNow(ctx context.Context, monitor *models.Monitor) *models.Heartbeat {
+	heartbeat := m.Ping(ctx, monitor)
+	if heartbeat == nil {
+		return nil
+	}
+	m.save(ctx, monitor, heartbeat, 1)
+	return heartbeat
+}
+
+// check runs a single check of the monitor and returns the delay until
+// the job should run again.
+func (m *Monitor) check(j *job, monitor *models.Monitor) time.Duration {
+	interval := time.Duration(monitor.Frequency) * time.Second
+	heartbeat := m.Ping(m.ctx, monitor)
+	if heartbeat == nil {
+		return interval
+	}
+
+	switch heartbeat.Status {
+	case "red":
+		if j.failures == 0 {
+			j.first = heartbeat
+		}
+		j.failures++
+		j.slow = 0
+	case "orange":
+		if j.slow == 0 {
+			j.first = heartbeat
+		}
+		j.failures = 0
+		j.slow++
+	default:
+		j.failures, j.slow = 0, 0
+		j.first = nil
+	}
+	m.save(m.ctx, monitor, heartbeat, j.slow)
 
+	// Failures are only confirmed as downtime once the
+	// configured number of retries has been exhausted.
+	if j.failures > 0 && j.failures <= monitor.Retries {
+		log.Printf("Monitor %d check failed, retry %d of %d", monitor.ID, j.failures, monitor.Retries)
+		if monitor.RetryInterval > 0 {
+			interval = time.Duration(monitor.RetryInterval) * time.Second
+		}
+		return interval
+	}
+
+	// The monitor was reconfigured while the check was running, so the
+	// result no longer says anything about its current status.
+	if m.removed(j) {
+		return interval
+	}
+
+	status := heartbeat.Status
+	first := heartbeat
+	if status != "green" && j.first != nil {
+		first = j.first
+	}
+	m.updateStatus(m.ctx, monitor, heartbeat, first, status)
+
+	return interval
+}
+
+var incidentTypes = map[string]string{
+	"green":  "UP",
+	"orange": "DEGRADED",
+	"red":    "DOWN",
+}
+
+// updateStatus records the status of the monitor. A failing status opens
+// an incident starting at the first failed heartbeat, which stays open
+// until the monitor recovers or fails differently. Incidents opened
+// during maintenance are tagged with the window and alert no one; if the
+// monitor is still failing once the window is over, a new incident is
+// opened that does.
+func (m *Monitor) updateStatus(ctx context.Context, monitor *models.Monitor, heartbeat, first *models.Heartbeat, status string) {
 	id := monitor.ID
 
-	goroutineStopChannel := make(chan struct{})
-	m.goroutines[id] = goroutineStopChannel
+	if monitor.Status != status {
+		err := m.db.UpdateMonitorStatus(ctx, id, status)
+		if err != nil {
+			log.Printf("Error when trying to update monitor status: %v", err.Error())
+		} else {
+			monitor.Status = status
+		}
+	}
 
-	m.stopWaitGroup.Add(1)
+	open, err := m.db.OpenIncidentByMonitorId(ctx, id)
+	if err != nil && !svcerr.IsNotFound(err) {
+		log.Printf("Error when trying to retrieve incident: %v", err.Error())
+		return
+	}
 
-	go func() {
-		defer func() {
-			m.stopWaitGroup.Done()
-			m.mutex.Lock()
-			delete(m.goroutines, id)
-			m.mutex.Unlock()
-		}()
-
-		for {
-			select {
-			case <-m.stopChannel:
-				fmt.Printf("Goroutine with ID %d stopped\n", id)
-				return
-			case <-goroutineStopChannel:
-				fmt.Printf("Goroutine with ID %d stopped by request\n", id)
-				return
-			default:
-				monitor, err := m.db.FindMonitorById(id)
-				if err != nil {
-					log.Printf("Error retrieving updated monitor data: %v", err)
-					continue
-				}
-				if monitor.Status != "yellow" {
-					heartbeat := m.Ping(monitor)
-
-					incidents, err := m.db.LatestIncidentByMonitorId(id)
-					if err != nil {
-						log.Printf("Error when trying to retrieve incident: %v", err.Error())
-					}
-
-					if incidents == nil || (incidents.IsPositive != (heartbeat.Status == "green")) {
-						var incidentType string
-						if heartbeat.Status == "green" {
-							incidentType = "UP"
-						} else {
-							incidentType = "DOWN"
-						}
-						newIncident := &dto.SaveIncident{
-							Type: incidentType, Description: heartbeat.Message, IsPositive: heartbeat.Status == "green", MonitorId: id,
-						}
-
-						err = m.db.SaveIncident(newIncident)
-						if err != nil {
-							log.Printf("Error when trying to save incident: %v", err.Error())
-						}
-
-						notificationChannels, err := m.db.FindNotificationChannelsByMonitorId(id)
-						if err != nil {
-							log.Printf("Error when trying to retrieve notificationChannels: %v", err.Error())
-						}
-
-						discordMessage := alerts.DiscordAlertMessage(heartbeat, monitor)
-						if err == nil {
-							for _, v := range notificationChannels {
-								jsonData, err := json.Marshal(discordMessage)
-								if err == nil {
-									_, err := http.Post(v.Data.WebhookUrl, "application/json", strings.NewReader(string(jsonData)))
-									if err != nil {
-										log.Printf("Error when trying to send heartbeat to webhook: %v", err.Error())
-									}
-								} else {
-									log.Printf("Error when trying to convert heartbeat to JSON: %v", err.Error())
-								}
-							}
-						} else {
-							log.Printf("Error retrieving notification channels: %v", err)
-						}
-					}
-
-				}
-				time.Sleep(time.Duration(monitor.Frequency) * time.Second)
+	incidentType := incidentTypes[status]
+	if open != nil && open.Type == incidentType && (open.MaintenanceId == nil || heartbeat.MaintenanceId != nil) {
+		if heartbeat.MaintenanceId == nil {
+			m.remind(ctx, monitor, heartbeat, open)
+		}
+		return
+	}
+
+	if open == nil && incidentType == "UP" {
+		return
+	}
+
+	now := time.Now()
+	alert := &alerts.Alert{Type: incidentType, Monitor: monitor, Heartbeat: heartbeat, Time: now}
+
+	if open != nil {
+		if err := m.db.ResolveIncident(ctx, open, now); err != nil {
+			log.Printf("Error when trying to resolve incident: %v", err.Error())
+		}
+		// Channels never heard of incidents opened during maintenance.
+		if open.MaintenanceId == nil {
+			alert.Resolved = open
+		}
+		if incidentType == "UP" {
+			alert.Incident = open
+			alert.Duration = time.Duration(open.Duration) * time.Second
+		}
+	}
+
+	if incidentType != "UP" {
+		newIncident := &dto.SaveIncident{
+			Type: incidentType, Cause: heartbeat.Message, MonitorId: id, HeartbeatId: first.ID, StartedAt: first.Timestamp,
+			MaintenanceId: heartbeat.MaintenanceId,
+		}
+		// An outage lasting past the maintenance starts when the window ends.
+		if open != nil && open.Type == incidentType {
+			newIncident.HeartbeatId, newIncident.StartedAt = heartbeat.ID, heartbeat.Timestamp
+		}
+
+		incident, err := m.db.SaveIncident(ctx, newIncident)
+		if err != nil {
+			log.Printf("Error when trying to save incident: %v", err.Error())
+		}
+		alert.Incident = incident
+	}
+
+	if alert.Incident != nil && alert.Incident.MaintenanceId != nil {
+		log.Printf("Monitor %d is under maintenance, not sending %v alert", id, incidentType)
+		// The incident the channels were alerted about is still resolved
+		// where it was opened, so that it doesn't stay open for them.
+		if open := alert.Resolved; open != nil {
+			resolution := &alerts.Alert{
+				Type: "UP", Monitor: monitor, Heartbeat: heartbeat, Incident: open, Resolved: open, Time: now,
+				Duration: time.Duration(open.Duration) * time.Second,
 			}
+			m.notify(ctx, resolution, alerts.Resolves)
 		}
-	}()
+		return
+	}
+
+	m.Notify(ctx, alert)
 }
 
-func (m *Monitor) StopGoroutine(id int) {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+// Notify queues the alert for every notification channel of the monitor,
+// the dispatcher takes care of delivering it.
+func (m *Monitor) Notify(ctx context.Context, alert *alerts.Alert) {
+	m.notify(ctx, alert, nil)
+}
+
+// notify queues the alert for the notification channels of its monitor
+// whose provider passes the filter, or for all of them without one.
+func (m *Monitor) notify(ctx context.Context, alert *alerts.Alert, filter func(provider string) bool) {
+	notificationChannels, err := m.db.FindNotificationChannelsByMonitorId(ctx, alert.Monitor.ID)
+	if err != nil {
+		log.Printf("Error when trying to retrieve notificationChannels: %v", err.Error())
+		return
+	}
 
-	if stopCh, exists := m.goroutines[id]; exists {
-		close(stopCh)
-	} else {
-		fmt.Printf("Goroutine with ID %d not found\n", id)
+	if len(notificationChannels) == 0 {
+		return
 	}
+
+	alert.Monitor = alert.Monitor.Redacted()
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		log.Printf("Error when trying to encode alert: %v", err.Error())
+		return
+	}
+
+	// Events about an incident are recorded as such, not as its status.
+	entryType := alert.Type
+	if alert.Event != "" {
+		entryType = alert.Event
+	}
+
+	for _, v := range notificationChannels {
+		if filter != nil && !filter(v.Provider) {
+			continue
+		}
+
+		id, err := strconv.Atoi(v.ID)
+		if err != nil {
+			log.Printf("Invalid notification channel id %v", v.ID)
+			continue
+		}
+
+		entry := &models.OutboxEntry{NotificationId: id, Type: entryType, Payload: payload}
+		if alert.Incident != nil {
+			entry.IncidentId = alert.Incident.ID
+		}
+
+		if err := m.db.EnqueueNotification(ctx, entry); err != nil {
+			log.Printf("Error when trying to queue alert for notification channel %v: %v", v.ID, err.Error())
+		}
+	}
+
+	m.dispatcher.Wake()
 }
 
-func (m *Monitor) StartGoroutineSetup() {
-	monitors, err := m.db.RetrieveMonitors()
+// remind queues a reminder of the open incident for every notification
+// channel whose reminder interval has passed again since it was opened,
+// until someone acknowledges it. Reminders missed while upstat was not
+// running are skipped.
+func (m *Monitor) remind(ctx context.Context, monitor *models.Monitor, heartbeat *models.Heartbeat, incident *models.Incident) {
+	if incident.StartedAt == nil || incident.AcknowledgedAt != nil {
+		return
+	}
+
+	states, err := m.db.ReminderStates(ctx, monitor.ID, incident.ID)
+	if err != nil {
+		log.Printf("Error when trying to retrieve reminder states: %v", err.Error())
+		return
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(*incident.StartedAt)
+	queued := false
+	for _, state := range states {
+		reminder := int(elapsed / (time.Duration(state.RemindInterval) * time.Minute))
+		if reminder <= state.Reminders || (state.RemindMax > 0 && reminder > state.RemindMax) {
+			continue
+		}
+
+		alert := &alerts.Alert{
+			Type: incident.Type, Monitor: monitor.Redacted(), Heartbeat: heartbeat, Incident: incident,
+			Time: now, Duration: elapsed, Reminder: reminder,
+		}
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			log.Printf("Error when trying to encode alert: %v", err.Error())
+			return
+		}
+
+		entry := &models.OutboxEntry{
+			NotificationId: state.NotificationId, IncidentId: incident.ID, Reminder: reminder, Type: alert.Type, Payload: payload,
+		}
+		if err := m.db.EnqueueNotification(ctx, entry); err != nil {
+			log.Printf("Error when trying to queue reminder for notification channel %v: %v", state.NotificationId, err.Error())
+			continue
+		}
+		queued = true
+	}
+
+	if queued {
+		m.dispatcher.Wake()
+	}
+}
+
+// Start loads the monitors, schedules the active ones and starts the
+// scheduler loop together with its pool of check workers.
+func (m *Monitor) Start() {
+	monitors, err := m.db.RetrieveMonitors(m.ctx)
 	if err != nil {
 		log.Println("Error when trying to retrieve monitors")
 		log.Println(err.Error())
 	}
 
+	m.stopWaitGroup.Add(m.workers + 1)
+	for range m.workers {
+		go m.worker()
+	}
+	go m.run()
+
+	now := time.Now()
+	m.mutex.Lock()
+	for _, v := range monitors {
+		if v.Active {
+			// Spread the first checks over one interval so that a
+			// restart doesn't run every monitor at the same time.
+			next := now
+			if interval := time.Duration(v.Frequency) * time.Second; interval > 0 {
+				next = next.Add(rand.N(interval))
+			}
+			m.push(&job{monitor: v, next: next})
+		}
+	}
+	m.mutex.Unlock()
+
 	for _, v := range monitors {
-		if v.Status != "yellow" {
-			m.StartGoroutine(v)
+		if !v.Active && v.ResumeAt != nil {
+			m.ScheduleResume(v)
 		}
 	}
 }
 
-func (m *Monitor) Ping(monitor *models.Monitor) *models.Heartbeat {
-	heartbeat := new(models.Heartbeat)
-	heartbeat.MonitorId = monitor.ID
-	fmt.Printf("Pinging %v at %v \n", monitor.Name, monitor.Url)
-	startTime := time.Now()
-	response, err := http.Get(monitor.Url)
-	if err != nil {
-		heartbeat.Status = "red"
-		heartbeat.StatusCode = "error"
-		heartbeat.Message = "unable to ping"
-		heartbeat.Latency = 0
-		if monitor.Status != "red" {
-			err := m.db.UpdateMonitorStatus(monitor.ID, "red")
-			if err != nil {
-				log.Printf("Error when trying to update monitor status: %v", err.Error())
-			}
+// Stop stops the scheduler, cancels the checks that are in flight and
+// waits for the workers to exit, giving up once ctx is done.
+func (m *Monitor) Stop(ctx context.Context) error {
+	m.stopOnce.Do(func() {
+		m.mutex.Lock()
+		for id := range m.resumeTimers {
+			m.cancelResume(id)
 		}
-	} else {
-		heartbeat.Status = "green"
-		heartbeat.StatusCode = strings.Split(response.Status, " ")[0]
-		heartbeat.Message = strings.Split(response.Status, " ")[1]
-		latency := time.Since(startTime).Milliseconds()
-		heartbeat.Latency = int(latency)
-		defer response.Body.Close()
-
-		if monitor.Status != "green" {
-			err := m.db.UpdateMonitorStatus(monitor.ID, "green")
-			if err != nil {
-				log.Printf("Error when trying to update monitor status: %v", err.Error())
-			}
+		m.mutex.Unlock()
+
+		close(m.stopChannel)
+		m.cancel()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		m.stopWaitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ScheduleResume resumes a paused monitor once its resume time is reached.
+func (m *Monitor) ScheduleResume(monitor *models.Monitor) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if monitor.ResumeAt == nil {
+		return
+	}
+
+	id := monitor.ID
+	m.cancelResume(id)
+	m.resumeTimers[id] = time.AfterFunc(time.Until(*monitor.ResumeAt), func() {
+		m.mutex.Lock()
+		delete(m.resumeTimers, id)
+		m.mutex.Unlock()
+
+		if err := m.db.ResumeMonitor(m.ctx, id); err != nil {
+			log.Printf("Error when trying to resume monitor %d: %v", id, err.Error())
+			return
+		}
+
+		monitor, err := m.db.FindMonitorById(m.ctx, id)
+		if err != nil {
+			log.Printf("Error retrieving resumed monitor data: %v", err)
+			return
 		}
+		m.Schedule(monitor)
+	})
+}
+
+// cancelResume must be called with the mutex held.
+func (m *Monitor) cancelResume(id int) {
+	if timer, exists := m.resumeTimers[id]; exists {
+		timer.Stop()
+		delete(m.resumeTimers, id)
+	}
+}
+
+// Ping runs the check of the monitor and returns the resulting heartbeat
+// without saving it. It returns nil when the check was cancelled, so that a shutdown is not
+// recorded as downtime.
+func (m *Monitor) Ping(ctx context.Context, monitor *models.Monitor) *models.Heartbeat {
+	fmt.Printf("Pinging %v at %v \n", monitor.Name, monitor.Url)
+	result := checks.Run(ctx, monitor)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	heartbeat := &models.Heartbeat{
+		MonitorId:  monitor.ID,
+		Timestamp:  time.Now().UTC(),
+		StatusCode: result.StatusCode,
+		Status:     result.Status,
+		Latency:    result.Latency,
+		Message:    result.Message,
+	}
+
+	if heartbeat.Status == "green" && monitor.LatencyThreshold > 0 && heartbeat.Latency > monitor.LatencyThreshold {
+		heartbeat.Status = "orange"
+		heartbeat.Message = fmt.Sprintf("latency of %vms exceeds the %vms threshold", heartbeat.Latency, monitor.LatencyThreshold)
 	}
-	heartbeat.Timestamp = time.Now().UTC()
-	err = m.db.SaveHeartbeat(heartbeat)
+
+	calendar, err := m.maintenanceCalendar(ctx, monitor.ID)
 	if err != nil {
-		log.Printf("Error when trying to save heartbeat: %v", err.Error())
+		log.Printf("Error when trying to retrieve maintenance windows: %v", err.Error())
+	} else if window := calendar.Active(heartbeat.Timestamp); window != nil {
+		heartbeat.MaintenanceId = &window.ID
 	}
 
 	return heartbeat
 }
+
+// save stores the heartbeat of a check. A slow response only degrades the
+// monitor after enough consecutive slow checks; until then it is stored
+// as up, so that it does not count as degraded time.
+func (m *Monitor) save(ctx context.Context, monitor *models.Monitor, heartbeat *models.Heartbeat, slow int) {
+	if heartbeat.Status == "orange" && slow < max(monitor.DegradedAfter, 1) {
+		heartbeat.Status = "green"
+	}
+
+	err := m.db.SaveHeartbeat(ctx, heartbeat)
+	if err != nil {
+		log.Printf("Error when trying to save heartbeat: %v", err.Error())
+	}
+}
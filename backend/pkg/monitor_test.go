@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/internal/models"
+	"github.com/chamanbravo/upstat/pkg/alerts"
+	"github.com/chamanbravo/upstat/svcerr"
+)
+
+// fakeIncidents keeps the incidents of a monitor and the alerts queued
+// about them in memory.
+type fakeIncidents struct {
+	DB
+	channels  []models.Notification
+	incidents []*models.Incident
+	queued    []*models.OutboxEntry
+}
+
+func (f *fakeIncidents) UpdateMonitorStatus(ctx context.Context, id int, status string) error {
+	return nil
+}
+
+func (f *fakeIncidents) OpenIncidentByMonitorId(ctx context.Context, id int) (*models.Incident, error) {
+	for _, incident := range slices.Backward(f.incidents) {
+		if incident.ResolvedAt == nil {
+			copied := *incident
+			return &copied, nil
+		}
+	}
+
+	return nil, svcerr.ErrNoIncidentsFound
+}
+
+func (f *fakeIncidents) SaveIncident(ctx context.Context, in *dto.SaveIncident) (*models.Incident, error) {
+	incident := &models.Incident{
+		ID: len(f.incidents) + 1, Type: in.Type, Cause: in.Cause, MonitorId: in.MonitorId, StartedAt: &in.StartedAt,
+		MaintenanceId: in.MaintenanceId,
+	}
+	f.incidents = append(f.incidents, incident)
+
+	copied := *incident
+	return &copied, nil
+}
+
+func (f *fakeIncidents) ResolveIncident(ctx context.Context, incident *models.Incident, resolvedAt time.Time) error {
+	incident.ResolvedAt = &resolvedAt
+	incident.Duration = int64(resolvedAt.Sub(*incident.StartedAt).Seconds())
+	f.incidents[incident.ID-1].ResolvedAt = &resolvedAt
+
+	return nil
+}
+
+func (f *fakeIncidents) FindNotificationChannelsByMonitorId(ctx context.Context, id int) ([]models.Notification, error) {
+	return f.channels, nil
+}
+
+func (f *fakeIncidents) EnqueueNotification(ctx context.Context, entry *models.OutboxEntry) error {
+	f.queued = append(f.queued, entry)
+	return nil
+}
+
+func (f *fakeIncidents) ReminderStates(ctx context.Context, monitorId, incidentId int) ([]*models.ReminderState, error) {
+	return nil, nil
+}
+
+func TestUpdateStatusMaintenance(t *testing.T) {
+	db := &fakeIncidents{channels: []models.Notification{
+		{ID: "1", Provider: "Slack"},
+		{ID: "2", Provider: "PagerDuty"},
+	}}
+	monitor := New(db, NewDispatcher(db))
+	window := 5
+
+	// Each step lists the alerts it queues as channel, type and incident,
+	// and for a resolution the incident it resolves.
+	steps := []struct {
+		name        string
+		status      string
+		maintenance *int
+		queued      []string
+		resolved    int
+	}{
+		{"down", "red", nil, []string{"1 DOWN 1", "2 DOWN 1"}, 0},
+		{"degraded during maintenance only resolves where it was opened", "orange", &window, []string{"2 UP 1"}, 1},
+		{"still degraded during maintenance", "orange", &window, nil, 0},
+		{"down during maintenance", "red", &window, nil, 0},
+		{"up during maintenance", "green", &window, nil, 0},
+		{"down during maintenance again", "red", &window, nil, 0},
+		{"still down as the window ends", "red", nil, []string{"1 DOWN 5", "2 DOWN 5"}, 0},
+		{"up", "green", nil, []string{"1 UP 5", "2 UP 5"}, 5},
+	}
+
+	for i, step := range steps {
+		db.queued = nil
+		heartbeat := &models.Heartbeat{ID: i + 1, Status: step.status, Timestamp: time.Now(), MaintenanceId: step.maintenance}
+		monitor.updateStatus(context.Background(), &models.Monitor{ID: 1}, heartbeat, heartbeat, step.status)
+
+		var queued []string
+		for _, entry := range db.queued {
+			queued = append(queued, fmt.Sprintf("%d %v %d", entry.NotificationId, entry.Type, entry.IncidentId))
+
+			alert := new(alerts.Alert)
+			if err := json.Unmarshal(entry.Payload, alert); err != nil {
+				t.Fatalf("%v: invalid payload: %v", step.name, err)
+			}
+			if resolved := alert.Resolved; (resolved == nil && step.resolved != 0) || (resolved != nil && resolved.ID != step.resolved) {
+				t.Errorf("%v: got resolved %+v, want incident %d", step.name, resolved, step.resolved)
+			}
+		}
+		if !slices.Equal(queued, step.queued) {
+			t.Errorf("%v: got %q queued, want %q", step.name, queued, step.queued)
+		}
+	}
+
+	if open := db.incidents[len(db.incidents)-1]; open.ResolvedAt == nil {
+		t.Errorf("got incident %d still open", open.ID)
+	}
+}
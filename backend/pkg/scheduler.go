@@ -0,0 +1,230 @@
+package pkg
+
+import (
+	"container/heap"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/chamanbravo/upstat/internal/dto"
+	"github.com/chamanbravo/upstat/internal/models"
+)
+
+const (
+	defaultCheckWorkers = 16
+	idleWait            = time.Minute
+	maxJitter           = 30 * time.Second
+)
+
+// job is a scheduled monitor check. Its fields are guarded by the Monitor
+// mutex, except for the failure counters which only the worker running
+// the check touches.
+type job struct {
+	monitor *models.Monitor
+	next    time.Time
+	index   int
+	running bool
+	removed bool
+
+	failures int
+	slow     int
+	// first is the first heartbeat of the current run of failed or
+	// slow checks, it marks the start of an incident.
+	first *models.Heartbeat
+}
+
+// jobQueue is a min-heap of jobs ordered by their next run.
+type jobQueue []*job
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x any) {
+	j := x.(*job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	j.index = -1
+	*q = old[:n-1]
+	return j
+}
+
+func checkWorkers() int {
+	value := os.Getenv("CHECK_WORKERS")
+	if value == "" {
+		return defaultCheckWorkers
+	}
+
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 1 {
+		log.Printf("Invalid CHECK_WORKERS value %q, using %d workers", value, defaultCheckWorkers)
+		return defaultCheckWorkers
+	}
+
+	return workers
+}
+
+// jitter returns a random offset of up to a tenth of the interval, and at
+// most maxJitter, either way. Offsetting every run keeps monitors that were
+// added or restarted together from being checked in lockstep.
+func jitter(interval time.Duration) time.Duration {
+	spread := min(interval/10, maxJitter)
+	if spread <= 0 {
+		return 0
+	}
+
+	return rand.N(2*spread+1) - spread
+}
+
+// push must be called with the mutex held.
+func (m *Monitor) push(j *job) {
+	m.jobs[j.monitor.ID] = j
+	heap.Push(&m.queue, j)
+	m.wakeUp()
+}
+
+// remove must be called with the mutex held.
+func (m *Monitor) remove(j *job) {
+	j.removed = true
+	delete(m.jobs, j.monitor.ID)
+	if j.index >= 0 {
+		heap.Remove(&m.queue, j.index)
+	}
+}
+
+func (m *Monitor) removed(j *job) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return j.removed
+}
+
+func (m *Monitor) wakeUp() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run hands due jobs to the workers and sleeps until the next job is due
+// or the queue changes.
+func (m *Monitor) run() {
+	defer m.stopWaitGroup.Done()
+
+	timer := time.NewTimer(idleWait)
+	defer timer.Stop()
+
+	for {
+		due, wait := m.dueJobs()
+		for _, j := range due {
+			select {
+			case m.work <- j:
+			case <-m.stopChannel:
+				return
+			}
+		}
+		if len(due) > 0 {
+			continue
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-m.stopChannel:
+			return
+		case <-m.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+func (m *Monitor) dueJobs() ([]*job, time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	due := make([]*job, 0)
+	for m.queue.Len() > 0 {
+		j := m.queue[0]
+		if j.next.After(now) {
+			return due, j.next.Sub(now)
+		}
+		heap.Pop(&m.queue)
+		due = append(due, j)
+	}
+
+	return due, idleWait
+}
+
+func (m *Monitor) worker() {
+	defer m.stopWaitGroup.Done()
+
+	for {
+		select {
+		case <-m.stopChannel:
+			return
+		case j := <-m.work:
+			m.mutex.Lock()
+			if j.removed {
+				m.mutex.Unlock()
+				continue
+			}
+			j.running = true
+			monitor, planned := j.monitor, j.next
+			m.mutex.Unlock()
+
+			interval := m.check(j, monitor)
+
+			m.mutex.Lock()
+			j.running = false
+			if !j.removed {
+				// Keep the cadence of the schedule, unless the check
+				// ran so late that the next run is already overdue.
+				j.next = planned.Add(interval + jitter(interval))
+				if now := time.Now(); j.next.Before(now) {
+					j.next = now
+				}
+				heap.Push(&m.queue, j)
+				m.wakeUp()
+			}
+			m.mutex.Unlock()
+		}
+	}
+}
+
+// Stats reports how many checks are scheduled and how far behind
+// schedule the checks that are due but not yet running are.
+func (m *Monitor) Stats() dto.SchedulerStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	stats := dto.SchedulerStats{Workers: m.workers, Scheduled: len(m.jobs)}
+	for _, j := range m.jobs {
+		if j.running {
+			stats.Running++
+			continue
+		}
+
+		if lag := now.Sub(j.next); lag >= 0 {
+			stats.QueueDepth++
+			stats.Lag = max(stats.Lag, lag.Milliseconds())
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,34 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		spread   time.Duration
+	}{
+		{0, 0},
+		{time.Nanosecond, 0},
+		{30 * time.Second, 3 * time.Second},
+		{time.Minute, 6 * time.Second},
+		{24 * time.Hour, maxJitter},
+	}
+
+	for _, tt := range tests {
+		offsets := make(map[time.Duration]bool)
+		for range 100 {
+			offset := jitter(tt.interval)
+			if offset < -tt.spread || offset > tt.spread {
+				t.Fatalf("%v: got %v, want at most %v either way", tt.interval, offset, tt.spread)
+			}
+			offsets[offset] = true
+		}
+
+		if tt.spread > 0 && len(offsets) < 2 {
+			t.Errorf("%v: got the same offset every time", tt.interval)
+		}
+	}
+}
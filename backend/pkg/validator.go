@@ -3,8 +3,12 @@ package pkg
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
+	"github.com/chamanbravo/upstat/pkg/alerts"
+	"github.com/chamanbravo/upstat/pkg/checks"
+	"github.com/chamanbravo/upstat/pkg/maintenance"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -30,6 +34,26 @@ func newXValidator() *XValidator {
 		}
 		return name
 	})
+	v.RegisterValidation("monitortype", func(fl validator.FieldLevel) bool {
+		_, ok := checks.Get(fl.Field().String())
+		return ok
+	})
+	v.RegisterValidation("notificationprovider", func(fl validator.FieldLevel) bool {
+		return slices.Contains(alerts.Providers(), fl.Field().String())
+	})
+	v.RegisterValidation("template", func(fl validator.FieldLevel) bool {
+		_, err := alerts.ParseWebhookTemplate(fl.Field().String())
+		return err == nil
+	})
+	v.RegisterValidation("statuscode", func(fl validator.FieldLevel) bool {
+		_, _, err := checks.ParseStatusCodeRange(fl.Field().String())
+		return err == nil
+	})
+	// The rule is checked according to the Recurrence field next to it.
+	v.RegisterValidation("recurrencerule", func(fl validator.FieldLevel) bool {
+		recurrence := fl.Parent().FieldByName("Recurrence").String()
+		return maintenance.ValidateRule(recurrence, fl.Field().String()) == nil
+	})
 
 	return &XValidator{
 		validator: v,
@@ -50,7 +74,7 @@ func (v *XValidator) Validate(data interface{}) map[string]string {
 			elem.Error = true
 
 			// Customize error message for the error tags
-			if elem.Tag == "required" {
+			if elem.Tag == "required" || elem.Tag == "required_if" || elem.Tag == "required_with" || elem.Tag == "required_without" {
 				elem.Tag = fmt.Sprintf("%s is required", elem.FailedField)
 			}
 			if elem.Tag == "email" {
@@ -65,6 +89,30 @@ func (v *XValidator) Validate(data interface{}) map[string]string {
 			if elem.Tag == "oneof" {
 				elem.Tag = fmt.Sprintf("%s must be one of [%s]", elem.FailedField, strings.Join(strings.Split(err.Param(), " "), ", "))
 			}
+			if elem.Tag == "monitortype" {
+				elem.Tag = fmt.Sprintf("%s must be one of [%s]", elem.FailedField, strings.Join(checks.Types(), ", "))
+			}
+			if elem.Tag == "notificationprovider" {
+				elem.Tag = fmt.Sprintf("%s must be one of [%s]", elem.FailedField, strings.Join(alerts.Providers(), ", "))
+			}
+			if elem.Tag == "url" {
+				elem.Tag = fmt.Sprintf("%s must be a valid URL", elem.FailedField)
+			}
+			if elem.Tag == "template" {
+				elem.Tag = fmt.Sprintf("%s must be a valid template", elem.FailedField)
+			}
+			if elem.Tag == "statuscode" {
+				elem.Tag = fmt.Sprintf("%s must be a status code or a range like 200-299", elem.FailedField)
+			}
+			if elem.Tag == "recurrencerule" {
+				elem.Tag = fmt.Sprintf("%s must be a valid cron expression or RRULE", elem.FailedField)
+			}
+			if elem.Tag == "gtfield" {
+				elem.Tag = fmt.Sprintf("%s must be after %s", elem.FailedField, strings.ToLower(err.Param()[:1])+err.Param()[1:])
+			}
+			if elem.Tag == "timezone" {
+				elem.Tag = fmt.Sprintf("%s must be a valid time zone", elem.FailedField)
+			}
 
 			validationErrors = append(validationErrors, elem)
 		}
@@ -8,6 +8,19 @@ var (
 	ErrUserNotFound          = errors.New("user was not found")
 	ErrNoIncidentsFound      = errors.New("incidents were not found")
 	ErrNotificationsNotFound = errors.New("notifications channel was not found")
+	ErrIncidentClosed        = errors.New("incident is already acknowledged or resolved")
+
+	ErrStatusPageIncidentNotFound = errors.New("status page incident was not found")
+	ErrMaintenanceWindowNotFound  = errors.New("maintenance window was not found")
+	// ErrMaintenanceScopeNotFound is a monitor or status page a
+	// maintenance window is scoped to that doesn't exist.
+	ErrMaintenanceScopeNotFound = errors.New("maintenance window scope was not found")
+	// ErrStatusPageIncidentPageNotFound is a status page an incident is
+	// posted to that doesn't exist.
+	ErrStatusPageIncidentPageNotFound = errors.New("status page of incident was not found")
+	// ErrMonitorAuthTokenMissing is a monitor using bearer authentication
+	// without a token, given or saved.
+	ErrMonitorAuthTokenMissing = errors.New("authToken is required for bearer authentication")
 )
 
 func IsNotFound(err error) bool {
@@ -16,7 +29,9 @@ func IsNotFound(err error) bool {
 		errors.Is(err, ErrNotificationsNotFound),
 		errors.Is(err, ErrUserNotFound),
 		errors.Is(err, ErrNoIncidentsFound),
-		errors.Is(err, ErrStatusPageNotFound):
+		errors.Is(err, ErrStatusPageNotFound),
+		errors.Is(err, ErrStatusPageIncidentNotFound),
+		errors.Is(err, ErrMaintenanceWindowNotFound):
 		return true
 	default:
 		return false
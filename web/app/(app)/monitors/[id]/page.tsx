@@ -17,6 +17,7 @@ export const metadata: Metadata = {
 const statusColor: Record<string, string> = {
   green: "text-green-500",
   yellow: "text-yellow-500",
+  orange: "text-orange-500",
   red: "text-red-500",
 };
 
@@ -49,17 +50,35 @@ export default async function MonitorItem({ params, searchParams }: PageProps) {
           </Link>
         </Button>
         <div className="flex gap-4 items-center">
-          <SonarPing status={monitorInfo?.monitor?.status || ""} />
+          <SonarPing
+            status={
+              monitorInfo?.monitor?.active
+                ? monitorInfo?.monitor?.status || ""
+                : "yellow"
+            }
+          />
           <div className="flex flex-col gap-1">
             <h1 className="text-2xl font-semibold flex gap-1 items-center">
               {monitorInfo?.monitor?.name}
             </h1>
             <div className="flex gap-1 items-center">
-              <p className={statusColor[monitorInfo?.monitor?.status || ""]}>
-                {monitorInfo?.monitor?.status === "green"
+              <p
+                className={
+                  statusColor[
+                    monitorInfo?.monitor?.active
+                      ? monitorInfo?.monitor?.status || ""
+                      : "yellow"
+                  ]
+                }
+              >
+                {!monitorInfo?.monitor?.active
+                  ? "Paused"
+                  : monitorInfo?.monitor?.status === "green"
                   ? "Up"
                   : monitorInfo?.monitor?.status === "red"
                   ? "Down"
+                  : monitorInfo?.monitor?.status === "orange"
+                  ? "Degraded"
                   : "Paused"}
               </p>
               <DotIcon className="text-muted-foreground h-4 w-4" />
@@ -82,7 +101,7 @@ export default async function MonitorItem({ params, searchParams }: PageProps) {
         </div>
         <div className="flex flex-col gap-6 mt-8">
           <div className="flex gap-4 items-center">
-            <ChangeStatus id={id} status={monitorInfo?.monitor?.status} />
+            <ChangeStatus id={id} active={monitorInfo?.monitor?.active} />
             <Button
               variant="ghost"
               className="w-fit text-muted-foreground p-2 flex gap-1 h-7"
@@ -63,11 +63,21 @@ export default async function EditMonitor({ params }: PageProps) {
               {monitorInfo?.monitor?.name}
             </p>
             <DotIcon className="text-muted-foreground h-4 w-4" />
-            <p className={`text-${monitorInfo?.monitor?.status}-500 text-sm`}>
-              {monitorInfo?.monitor?.status === "green"
+            <p
+              className={`text-${
+                monitorInfo?.monitor?.active
+                  ? monitorInfo?.monitor?.status
+                  : "yellow"
+              }-500 text-sm`}
+            >
+              {!monitorInfo?.monitor?.active
+                ? "Paused"
+                : monitorInfo?.monitor?.status === "green"
                 ? "Up"
                 : monitorInfo?.monitor?.status === "red"
                 ? "Down"
+                : monitorInfo?.monitor?.status === "orange"
+                ? "Degraded"
                 : "Paused"}
             </p>
           </div>
@@ -75,7 +85,7 @@ export default async function EditMonitor({ params }: PageProps) {
       </div>
 
       <div className="flex gap-4 items-centerm">
-        <ChangeStatus id={id} status={monitorInfo?.monitor?.status} />
+        <ChangeStatus id={id} active={monitorInfo?.monitor?.active} />
         <DeleteMonitor id={id} />
       </div>
 
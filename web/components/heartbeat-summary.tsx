@@ -27,6 +27,8 @@ export default function HeartbeatSummary({ heartbeat }: { heartbeat: any }) {
                   h?.status
                     ? h?.status === "green"
                       ? "bg-green-400"
+                      : h?.status === "orange"
+                      ? "bg-orange-400"
                       : "bg-red-400"
                     : "bg-gray-400"
                 } ${h && "hover:scale-125"} `}
@@ -8,10 +8,10 @@ import { useRouter } from "next/navigation";
 
 interface Props {
   id: string;
-  status?: string;
+  active?: boolean;
 }
 
-export default function ChangeStatus({ id, status }: Props) {
+export default function ChangeStatus({ id, active }: Props) {
   const router = useRouter();
 
   const pauseMonitor = async () => {
@@ -47,14 +47,14 @@ export default function ChangeStatus({ id, status }: Props) {
       variant="ghost"
       className="w-fit text-muted-foreground p-2 flex gap-1 h-7"
       onClick={() => {
-        if (status === "green" || status === "red") {
+        if (active) {
           pauseMonitor();
         } else {
           resumeMonitor();
         }
       }}
     >
-      {status === "green" || status === "red" ? (
+      {active ? (
         <>
           <PauseCircle className="h-4 w-4" />
           Pause this monitor
@@ -38,15 +38,18 @@ export default function DataTable({ data }: Props) {
                 >
                   <div
                     className={cn(`w-[6px] h-[6px] rounded-[50%]`, {
-                      "bg-green-500": i.status === "green",
-                      "bg-yellow-500": i.status === "yellow",
-                      "bg-red-500": i.status === "red",
+                      "bg-green-500": i.active && i.status === "green",
+                      "bg-yellow-500": !i.active,
+                      "bg-orange-500": i.active && i.status === "orange",
+                      "bg-red-500": i.active && i.status === "red",
                     })}
                     title={
-                      i.status === "green"
-                        ? "Up"
-                        : i.status === "yellow"
+                      !i.active
                         ? "Paused"
+                        : i.status === "green"
+                        ? "Up"
+                        : i.status === "orange"
+                        ? "Degraded"
                         : "Down"
                     }
                   />
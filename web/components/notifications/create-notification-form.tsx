@@ -28,6 +28,8 @@ const NotificationFormSchema = z.object({
   webhookUrl: z
     .string({ required_error: "This field may not be blank." })
     .min(8, { message: "This field may not be blank." }),
+  remindInterval: z.coerce.number().int().min(0),
+  remindMax: z.coerce.number().int().min(0),
 });
 
 type NotificationFormValues = z.infer<typeof NotificationFormSchema>;
@@ -49,6 +51,8 @@ export default function CreateNotificationForm({ defaultValues }: Props) {
     defaultValues: {
       name: defaultValues?.name,
       webhookUrl: defaultValues?.data.webhookUrl,
+      remindInterval: defaultValues?.remindInterval || 0,
+      remindMax: defaultValues?.remindMax || 0,
     },
   });
 
@@ -67,6 +71,8 @@ export default function CreateNotificationForm({ defaultValues }: Props) {
             data: {
               webhookUrl: formData.webhookUrl,
             },
+            remindInterval: formData.remindInterval,
+            remindMax: formData.remindMax,
           }),
         }
       );
@@ -145,6 +151,37 @@ export default function CreateNotificationForm({ defaultValues }: Props) {
               )}
             />
           )}
+          <FormField
+            control={form.control}
+            name="remindInterval"
+            render={({ field }) => (
+              <FormItem className="space-y-1">
+                <FormLabel>Remind every (minutes)</FormLabel>
+                <FormControl>
+                  <Input type="number" min={0} {...field} />
+                </FormControl>
+                <FormDescription>
+                  Repeat the alert while the monitor stays down. 0 disables
+                  reminders.
+                </FormDescription>
+                <FormMessage />
+              </FormItem>
+            )}
+          />
+          <FormField
+            control={form.control}
+            name="remindMax"
+            render={({ field }) => (
+              <FormItem className="space-y-1">
+                <FormLabel>Maximum reminders</FormLabel>
+                <FormControl>
+                  <Input type="number" min={0} {...field} />
+                </FormControl>
+                <FormDescription>0 means no limit.</FormDescription>
+                <FormMessage />
+              </FormItem>
+            )}
+          />
 
           <Button type="submit" disabled={loading}>
             {loading ? "Loading..." : "Submit"}
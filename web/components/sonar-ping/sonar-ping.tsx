@@ -8,12 +8,14 @@ interface Props {
 const sonarColor: Record<string, string> = {
   green: "bg-green-500",
   yellow: "bg-yellow-500",
+  orange: "bg-orange-500",
   red: "bg-red-500",
 };
 
 const sonarBorderColor: Record<string, string> = {
   green: "border-green-500",
   yellow: "border-yellow-500",
+  orange: "border-orange-500",
   red: "border-red-500",
 };
 
@@ -1281,6 +1281,10 @@ export interface components {
             method?: string;
             frequency?: number;
             status?: string;
+            active?: boolean;
+            pausedBy?: string;
+            pausedAt?: string;
+            resumeAt?: string;
         };
         MonitorInfoOut: {
             monitor?: components["schemas"]["Monitor"];